@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS "prediction_scheduled_at_idx";
+
+ALTER TABLE "prediction" DROP COLUMN IF EXISTS "featured";
\ No newline at end of file
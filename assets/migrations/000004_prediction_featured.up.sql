@@ -0,0 +1,3 @@
+ALTER TABLE "prediction" ADD COLUMN "featured" boolean NOT NULL DEFAULT false;
+
+CREATE INDEX "prediction_scheduled_at_idx" ON "prediction" ("scheduled_at");
\ No newline at end of file
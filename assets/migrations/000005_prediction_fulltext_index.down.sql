@@ -0,0 +1 @@
+DROP INDEX IF EXISTS "prediction_fulltext_search_idx";
\ No newline at end of file
@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS "prediction_fulltext_search_idx" ON "prediction" USING GIN ("fulltext_search");
\ No newline at end of file
@@ -0,0 +1,6 @@
+ALTER TABLE "prediction" DROP COLUMN IF EXISTS "fixture_id";
+
+DROP TABLE IF EXISTS "fixture";
+DROP TABLE IF EXISTS "team";
+DROP TABLE IF EXISTS "season";
+DROP TABLE IF EXISTS "competition";
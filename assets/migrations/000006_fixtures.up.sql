@@ -0,0 +1,55 @@
+CREATE TABLE "competition" (
+    "id" bigserial PRIMARY KEY,
+    "name" text NOT NULL,
+    "slug" text UNIQUE NOT NULL,
+    "country" text NOT NULL DEFAULT '',
+    "created_at" timestamptz NOT NULL DEFAULT (now()),
+    "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "season" (
+    "id" bigserial PRIMARY KEY,
+    "competition_id" bigint NOT NULL REFERENCES "competition" ("id") ON DELETE CASCADE,
+    "name" text NOT NULL,
+    "starts_on" date NOT NULL,
+    "ends_on" date NOT NULL,
+    "created_at" timestamptz NOT NULL DEFAULT (now()),
+    "updated_at" timestamptz NOT NULL DEFAULT (now()),
+    UNIQUE ("competition_id", "name"),
+    CHECK ("starts_on" <= "ends_on")
+);
+
+CREATE TABLE "team" (
+    "id" bigserial PRIMARY KEY,
+    "name" text NOT NULL,
+    "slug" text UNIQUE NOT NULL,
+    "short_name" text NOT NULL DEFAULT '',
+    "country" text NOT NULL DEFAULT '',
+    "created_at" timestamptz NOT NULL DEFAULT (now()),
+    "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "fixture" (
+    "id" bigserial PRIMARY KEY,
+    "season_id" bigint NOT NULL REFERENCES "season" ("id") ON DELETE CASCADE,
+    "home_team_id" bigint NOT NULL REFERENCES "team" ("id") ON DELETE RESTRICT,
+    "away_team_id" bigint NOT NULL REFERENCES "team" ("id") ON DELETE RESTRICT,
+    "venue" text NOT NULL DEFAULT '',
+    "kickoff_at" timestamptz NOT NULL,
+    "status" text NOT NULL DEFAULT 'scheduled',
+    "home_score" integer,
+    "away_score" integer,
+    "created_at" timestamptz NOT NULL DEFAULT (now()),
+    "updated_at" timestamptz NOT NULL DEFAULT (now()),
+    CHECK ("home_team_id" <> "away_team_id"),
+    CHECK ("status" IN ('scheduled', 'live', 'finished', 'postponed', 'cancelled')),
+    CHECK ("status" <> 'finished' OR ("home_score" IS NOT NULL AND "away_score" IS NOT NULL))
+);
+
+CREATE INDEX "fixture_kickoff_at_idx" ON "fixture" ("kickoff_at");
+CREATE INDEX "fixture_home_team_id_idx" ON "fixture" ("home_team_id");
+CREATE INDEX "fixture_away_team_id_idx" ON "fixture" ("away_team_id");
+
+ALTER TABLE "prediction" ADD COLUMN "fixture_id" bigint REFERENCES "fixture" ("id") ON DELETE SET NULL;
+
+CREATE INDEX "prediction_fixture_id_idx" ON "prediction" ("fixture_id");
@@ -0,0 +1,5 @@
+ALTER TABLE "prediction"
+    DROP CONSTRAINT IF EXISTS "prediction_market_check",
+    DROP COLUMN IF EXISTS "line",
+    DROP COLUMN IF EXISTS "selection",
+    DROP COLUMN IF EXISTS "market";
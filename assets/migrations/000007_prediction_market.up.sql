@@ -0,0 +1,5 @@
+ALTER TABLE "prediction"
+    ADD COLUMN "market" text NOT NULL DEFAULT '1x2',
+    ADD COLUMN "selection" text NOT NULL DEFAULT 'home',
+    ADD COLUMN "line" decimal(5, 2) NOT NULL DEFAULT 0,
+    ADD CONSTRAINT "prediction_market_check" CHECK ("market" IN ('1x2', 'over_under', 'btts', 'asian_handicap', 'correct_score', 'double_chance'));
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS "prediction_outcome_idx";
+
+ALTER TABLE "prediction"
+    DROP CONSTRAINT IF EXISTS "prediction_outcome_check",
+    DROP COLUMN IF EXISTS "settled_at",
+    DROP COLUMN IF EXISTS "outcome";
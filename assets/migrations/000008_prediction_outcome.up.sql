@@ -0,0 +1,6 @@
+ALTER TABLE "prediction"
+    ADD COLUMN "outcome" text NOT NULL DEFAULT 'pending',
+    ADD COLUMN "settled_at" timestamptz,
+    ADD CONSTRAINT "prediction_outcome_check" CHECK ("outcome" IN ('pending', 'won', 'half_won', 'push', 'void', 'half_lost', 'lost'));
+
+CREATE INDEX "prediction_outcome_idx" ON "prediction" ("outcome");
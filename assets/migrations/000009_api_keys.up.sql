@@ -0,0 +1,20 @@
+CREATE TABLE "api_key" (
+    "id" bigserial PRIMARY KEY,
+    "owner" text NOT NULL,
+    "prefix" text NOT NULL,
+    "hash" bytea UNIQUE NOT NULL,
+    "scopes" text[] NOT NULL DEFAULT '{}',
+    "daily_quota" integer NOT NULL DEFAULT 1000,
+    "last_used_at" timestamptz,
+    "revoked_at" timestamptz,
+    "created_at" timestamptz NOT NULL DEFAULT (now()),
+    CHECK ("scopes" <@ ARRAY['read', 'write', 'admin']::text[]),
+    CHECK ("daily_quota" > 0)
+);
+
+CREATE TABLE "api_key_usage" (
+    "api_key_id" bigint NOT NULL REFERENCES "api_key" ("id") ON DELETE CASCADE,
+    "day" date NOT NULL,
+    "requests" integer NOT NULL DEFAULT 0,
+    PRIMARY KEY ("api_key_id", "day")
+);
@@ -0,0 +1,10 @@
+CREATE TABLE "users" (
+    "id" bigserial PRIMARY KEY,
+    "name" text NOT NULL,
+    "email" text NOT NULL,
+    "hashed_password" text NOT NULL,
+    "created_at" timestamptz NOT NULL DEFAULT (now()),
+    "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE UNIQUE INDEX "users_email_idx" ON "users" (lower("email"));
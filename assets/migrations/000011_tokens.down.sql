@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS "token";
+
+ALTER TABLE "users" DROP COLUMN IF EXISTS "verified";
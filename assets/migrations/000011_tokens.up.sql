@@ -0,0 +1,11 @@
+ALTER TABLE "users" ADD COLUMN "verified" boolean NOT NULL DEFAULT false;
+
+CREATE TABLE "token" (
+    "hash" bytea PRIMARY KEY,
+    "user_id" bigint NOT NULL REFERENCES "users" ("id") ON DELETE CASCADE,
+    "scope" text NOT NULL,
+    "expires_at" timestamptz NOT NULL,
+    "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX "token_user_id_scope_idx" ON "token" ("user_id", "scope");
@@ -0,0 +1,14 @@
+CREATE TABLE "bet" (
+    "user_id" bigint NOT NULL REFERENCES "users" ("id") ON DELETE CASCADE,
+    "prediction_id" bigint NOT NULL REFERENCES "prediction" ("id") ON DELETE CASCADE,
+    "stake" decimal(10, 2) NOT NULL DEFAULT 0,
+    "odds" decimal(5, 2),
+    "created_at" timestamptz NOT NULL DEFAULT (now()),
+    "updated_at" timestamptz NOT NULL DEFAULT (now()),
+    PRIMARY KEY ("user_id", "prediction_id"),
+    CHECK ("stake" >= 0),
+    CHECK ("odds" IS NULL OR "odds" > 1),
+    CHECK ("stake" = 0 OR "odds" IS NOT NULL)
+);
+
+CREATE INDEX "bet_prediction_id_idx" ON "bet" ("prediction_id");
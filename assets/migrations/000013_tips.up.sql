@@ -0,0 +1,20 @@
+CREATE TABLE "tip" (
+    "id" bigserial PRIMARY KEY,
+    "user_id" bigint NOT NULL REFERENCES "users" ("id") ON DELETE CASCADE,
+    "fixture_id" bigint NOT NULL REFERENCES "fixture" ("id") ON DELETE CASCADE,
+    "market" text NOT NULL,
+    "selection" text NOT NULL,
+    "line" decimal(5, 2) NOT NULL DEFAULT 0,
+    "odds" decimal(5, 2) NOT NULL,
+    "outcome" text NOT NULL DEFAULT 'pending',
+    "settled_at" timestamptz,
+    "created_at" timestamptz NOT NULL DEFAULT (now()),
+    "updated_at" timestamptz NOT NULL DEFAULT (now()),
+    UNIQUE ("user_id", "fixture_id"),
+    CONSTRAINT "tip_market_check" CHECK ("market" IN ('1x2', 'over_under', 'btts', 'asian_handicap', 'correct_score', 'double_chance')),
+    CONSTRAINT "tip_outcome_check" CHECK ("outcome" IN ('pending', 'won', 'half_won', 'push', 'void', 'half_lost', 'lost')),
+    CHECK ("odds" > 1)
+);
+
+CREATE INDEX "tip_fixture_id_idx" ON "tip" ("fixture_id");
+CREATE INDEX "tip_outcome_idx" ON "tip" ("outcome");
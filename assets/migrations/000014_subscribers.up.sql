@@ -0,0 +1,11 @@
+CREATE TABLE "subscriber" (
+    "id" bigserial PRIMARY KEY,
+    "email" text NOT NULL,
+    "confirmed_at" timestamptz,
+    "unsubscribed_at" timestamptz,
+    "last_digest_on" date,
+    "created_at" timestamptz NOT NULL DEFAULT (now()),
+    "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE UNIQUE INDEX "subscriber_email_idx" ON "subscriber" (lower("email"));
@@ -0,0 +1,17 @@
+CREATE TABLE "email_outbox" (
+    "id" bigserial PRIMARY KEY,
+    "recipient" text NOT NULL,
+    "headers" jsonb NOT NULL DEFAULT '{}',
+    "subject" text NOT NULL,
+    "plain_body" text NOT NULL,
+    "html_body" text NOT NULL DEFAULT '',
+    "status" text NOT NULL DEFAULT 'pending',
+    "attempts" integer NOT NULL DEFAULT 0,
+    "last_error" text NOT NULL DEFAULT '',
+    "next_attempt_at" timestamptz NOT NULL DEFAULT (now()),
+    "sent_at" timestamptz,
+    "created_at" timestamptz NOT NULL DEFAULT (now()),
+    "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX "email_outbox_pending_idx" ON "email_outbox" ("next_attempt_at") WHERE "status" = 'pending';
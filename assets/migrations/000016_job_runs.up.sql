@@ -0,0 +1,11 @@
+CREATE TABLE "job_run" (
+    "id" bigserial PRIMARY KEY,
+    "name" text NOT NULL,
+    "scheduled_for" timestamptz NOT NULL,
+    "started_at" timestamptz NOT NULL DEFAULT (now()),
+    "finished_at" timestamptz,
+    "error" text
+);
+
+CREATE UNIQUE INDEX "job_run_name_scheduled_for_idx" ON "job_run" ("name", "scheduled_for");
+CREATE INDEX "job_run_started_at_idx" ON "job_run" ("started_at");
@@ -0,0 +1,10 @@
+CREATE TABLE "team_rating" (
+    "fixture_id" bigint NOT NULL REFERENCES "fixture" ("id") ON DELETE CASCADE,
+    "team_id" bigint NOT NULL REFERENCES "team" ("id") ON DELETE CASCADE,
+    "position" integer NOT NULL,
+    "rating_before" double precision NOT NULL,
+    "rating_after" double precision NOT NULL,
+    PRIMARY KEY ("fixture_id", "team_id")
+);
+
+CREATE INDEX "team_rating_team_id_position_idx" ON "team_rating" ("team_id", "position");
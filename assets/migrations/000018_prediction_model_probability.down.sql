@@ -0,0 +1 @@
+ALTER TABLE "prediction" DROP COLUMN IF EXISTS "model_probability";
\ No newline at end of file
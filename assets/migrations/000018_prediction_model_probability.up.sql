@@ -0,0 +1 @@
+ALTER TABLE "prediction" ADD COLUMN "model_probability" double precision;
\ No newline at end of file
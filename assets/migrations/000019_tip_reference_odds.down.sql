@@ -0,0 +1 @@
+ALTER TABLE "tip" DROP COLUMN IF EXISTS "reference_odds";
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE "tip" ADD COLUMN "reference_odds" double precision;
\ No newline at end of file
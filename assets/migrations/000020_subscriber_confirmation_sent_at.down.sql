@@ -0,0 +1 @@
+ALTER TABLE "subscriber" DROP COLUMN IF EXISTS "confirmation_sent_at";
\ No newline at end of file
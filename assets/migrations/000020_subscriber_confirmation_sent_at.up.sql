@@ -0,0 +1 @@
+ALTER TABLE "subscriber" ADD COLUMN "confirmation_sent_at" timestamptz;
\ No newline at end of file
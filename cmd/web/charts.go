@@ -0,0 +1,135 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Chart dimensions in SVG user units. Templates set a viewBox, so charts scale
+// to the width of their container.
+const (
+	chartWidth        = 640
+	chartHeight       = 280
+	chartPaddingLeft  = 48
+	chartPaddingRight = 16
+	chartPaddingY     = 16
+	chartPaddingAxis  = 28
+)
+
+type chartPoint struct {
+	X     float64
+	Y     float64
+	Label string
+}
+
+type chartTick struct {
+	Position float64
+	Label    string
+}
+
+type chartAxis struct {
+	Min   float64
+	Max   float64
+	Ticks []chartTick
+}
+
+// lineChart holds a series of points already scaled to SVG coordinates.
+// Diagonal draws a dashed reference line from the bottom left corner to the
+// top right one.
+type lineChart struct {
+	Width    float64
+	Height   float64
+	Left     float64
+	Right    float64
+	Top      float64
+	Bottom   float64
+	Path     string
+	Points   []chartPoint
+	XTicks   []chartTick
+	YTicks   []chartTick
+	Diagonal bool
+}
+
+func newLineChart(points []chartPoint, x, y chartAxis) lineChart {
+	chart := lineChart{
+		Width:  chartWidth,
+		Height: chartHeight,
+		Left:   chartPaddingLeft,
+		Right:  chartWidth - chartPaddingRight,
+		Top:    chartPaddingY,
+		Bottom: chartHeight - chartPaddingAxis,
+	}
+
+	scaleX := func(v float64) float64 {
+		if x.Max == x.Min {
+			return (chart.Left + chart.Right) / 2
+		}
+		return chart.Left + (v-x.Min)/(x.Max-x.Min)*(chart.Right-chart.Left)
+	}
+
+	scaleY := func(v float64) float64 {
+		if y.Max == y.Min {
+			return (chart.Top + chart.Bottom) / 2
+		}
+		return chart.Bottom - (v-y.Min)/(y.Max-y.Min)*(chart.Bottom-chart.Top)
+	}
+
+	var path strings.Builder
+
+	for i, point := range points {
+		scaled := chartPoint{X: scaleX(point.X), Y: scaleY(point.Y), Label: point.Label}
+		chart.Points = append(chart.Points, scaled)
+
+		command := "L"
+		if i == 0 {
+			command = "M"
+		}
+		fmt.Fprintf(&path, "%s%.1f %.1f ", command, scaled.X, scaled.Y)
+	}
+
+	chart.Path = strings.TrimSpace(path.String())
+
+	for _, tick := range x.Ticks {
+		chart.XTicks = append(chart.XTicks, chartTick{Position: scaleX(tick.Position), Label: tick.Label})
+	}
+
+	for _, tick := range y.Ticks {
+		chart.YTicks = append(chart.YTicks, chartTick{Position: scaleY(tick.Position), Label: tick.Label})
+	}
+
+	return chart
+}
+
+// niceAxis returns an axis covering min and max with round tick values, using
+// at most about five ticks.
+func niceAxis(min, max float64, format string) chartAxis {
+	if min == max {
+		min, max = min-1, max+1
+	}
+
+	step := niceStep((max - min) / 5)
+
+	axis := chartAxis{
+		Min: math.Floor(min/step) * step,
+		Max: math.Ceil(max/step) * step,
+	}
+
+	for v := axis.Min; v <= axis.Max+step/2; v += step {
+		axis.Ticks = append(axis.Ticks, chartTick{Position: v, Label: fmt.Sprintf(format, v)})
+	}
+
+	return axis
+}
+
+func niceStep(rough float64) float64 {
+	magnitude := math.Pow(10, math.Floor(math.Log10(rough)))
+
+	for _, multiple := range []float64{1, 2, 5, 10} {
+		if rough <= multiple*magnitude {
+			return multiple * magnitude
+		}
+	}
+
+	return 10 * magnitude
+}
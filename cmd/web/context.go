@@ -0,0 +1,43 @@
+package main
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/afoejoe/football-predict/internal/database"
+)
+
+type contextKey string
+
+const (
+	apiKeyContextKey            = contextKey("apiKey")
+	authenticatedUserContextKey = contextKey("authenticatedUser")
+)
+
+func contextSetAPIKey(r *http.Request, key *database.APIKey) *http.Request {
+	ctx := context.WithValue(r.Context(), apiKeyContextKey, key)
+	return r.WithContext(ctx)
+}
+
+func contextGetAPIKey(r *http.Request) *database.APIKey {
+	key, ok := r.Context().Value(apiKeyContextKey).(*database.APIKey)
+	if !ok {
+		return nil
+	}
+
+	return key
+}
+
+func contextSetAuthenticatedUser(r *http.Request, user *database.User) *http.Request {
+	ctx := context.WithValue(r.Context(), authenticatedUserContextKey, user)
+	return r.WithContext(ctx)
+}
+
+func contextGetAuthenticatedUser(r *http.Request) *database.User {
+	user, ok := r.Context().Value(authenticatedUserContextKey).(*database.User)
+	if !ok {
+		return nil
+	}
+
+	return user
+}
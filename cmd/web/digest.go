@@ -0,0 +1,55 @@
+package main
+
+import (
+	"time"
+)
+
+// sendDigest emails tomorrow's predictions to every active subscriber. Each
+// subscriber is marked once their digest is sent, so running it again on the
+// same day only reaches the ones that were missed.
+func (app *application) sendDigest() error {
+	day := time.Now().UTC().Truncate(24 * time.Hour).Add(24 * time.Hour)
+
+	predictions, err := app.db.ListPredictions(day, day.Add(24*time.Hour))
+	if err != nil {
+		return err
+	}
+
+	if len(predictions) == 0 {
+		app.logger.Info("skipped digest with no predictions", "day", day.Format("2006-01-02"))
+		return nil
+	}
+
+	subscribers, err := app.db.ListDigestSubscribers(day)
+	if err != nil {
+		return err
+	}
+
+	for _, subscriber := range subscribers {
+		unsubscribeURL := app.newsletterURL("unsubscribe", signingNameNewsletterUnsubscribe, subscriber.ID)
+
+		data := app.newEmailData()
+		data["Day"] = day
+		data["Predictions"] = predictions
+		data["UnsubscribeURL"] = unsubscribeURL
+
+		headers := map[string]string{
+			"List-Unsubscribe":      "<" + unsubscribeURL + ">",
+			"List-Unsubscribe-Post": "List-Unsubscribe=One-Click",
+		}
+
+		err := app.mailer.SendWithHeaders(subscriber.Email, headers, data, "newsletter-digest.html")
+		if err != nil {
+			return err
+		}
+
+		err = app.db.SetSubscriberDigestSent(subscriber.ID, day)
+		if err != nil {
+			return err
+		}
+	}
+
+	app.logger.Info("sent digest", "day", day.Format("2006-01-02"), "subscribers", len(subscribers), "predictions", len(predictions))
+
+	return nil
+}
@@ -4,6 +4,11 @@ import (
 	"log/slog"
 	"net/http"
 	"runtime/debug"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/afoejoe/football-predict/internal/response"
+	"github.com/afoejoe/football-predict/internal/validator"
 )
 
 func (app *application) reportServerError(r *http.Request, err error) {
@@ -54,3 +59,68 @@ func (app *application) basicAuthenticationRequired(w http.ResponseWriter, r *ht
 	message := "You must be authenticated to access this resource"
 	http.Error(w, message, http.StatusUnauthorized)
 }
+
+func (app *application) crossOriginRequest(w http.ResponseWriter, r *http.Request) {
+	message := "Cross-origin requests are not allowed"
+	http.Error(w, message, http.StatusForbidden)
+}
+
+func (app *application) errorMessageJSON(w http.ResponseWriter, r *http.Request, status int, message string, headers http.Header) {
+	message = capitalize(message)
+
+	err := response.JSONWithHeaders(w, status, map[string]string{"Error": message}, headers)
+	if err != nil {
+		app.reportServerError(r, err)
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
+
+func (app *application) serverErrorJSON(w http.ResponseWriter, r *http.Request, err error) {
+	app.reportServerError(r, err)
+
+	message := "The server encountered a problem and could not process your request"
+	app.errorMessageJSON(w, r, http.StatusInternalServerError, message, nil)
+}
+
+func (app *application) notFoundJSON(w http.ResponseWriter, r *http.Request) {
+	message := "The requested resource could not be found"
+	app.errorMessageJSON(w, r, http.StatusNotFound, message, nil)
+}
+
+func (app *application) badRequestJSON(w http.ResponseWriter, r *http.Request, err error) {
+	app.errorMessageJSON(w, r, http.StatusBadRequest, err.Error(), nil)
+}
+
+func (app *application) failedValidationJSON(w http.ResponseWriter, r *http.Request, v validator.Validator) {
+	err := response.JSON(w, http.StatusUnprocessableEntity, v)
+	if err != nil {
+		app.serverErrorJSON(w, r, err)
+	}
+}
+
+func (app *application) invalidAPIKeyJSON(w http.ResponseWriter, r *http.Request) {
+	headers := make(http.Header)
+	headers.Set("WWW-Authenticate", "Bearer")
+
+	message := "A valid API key must be provided in the Authorization header"
+	app.errorMessageJSON(w, r, http.StatusUnauthorized, message, headers)
+}
+
+func (app *application) notPermittedJSON(w http.ResponseWriter, r *http.Request) {
+	message := "Your API key does not have the scope required to access this resource"
+	app.errorMessageJSON(w, r, http.StatusForbidden, message, nil)
+}
+
+func (app *application) rateLimitExceededJSON(w http.ResponseWriter, r *http.Request) {
+	message := "Your API key has used its daily quota"
+	app.errorMessageJSON(w, r, http.StatusTooManyRequests, message, nil)
+}
+
+func capitalize(s string) string {
+	r, size := utf8.DecodeRuneInString(s)
+	if size == 0 || r == utf8.RuneError {
+		return s
+	}
+
+	return string(unicode.ToUpper(r)) + s[size:]
+}
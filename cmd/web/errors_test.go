@@ -0,0 +1,27 @@
+package main
+
+import "testing"
+
+func TestCapitalize(t *testing.T) {
+	tests := []struct {
+		s    string
+		want string
+	}{
+		{"", ""},
+		{"a", "A"},
+		{"invalid API key", "Invalid API key"},
+		{"Already capitalised", "Already capitalised"},
+		{"équipe not found", "Équipe not found"},
+		{"ßtraße", "ßtraße"},
+		{"\xffbroken", "\xffbroken"},
+		{"42 is not a team", "42 is not a team"},
+	}
+
+	for _, tt := range tests {
+		got := capitalize(tt.s)
+
+		if got != tt.want {
+			t.Errorf("capitalize(%q) = %q; want %q", tt.s, got, tt.want)
+		}
+	}
+}
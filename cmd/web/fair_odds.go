@@ -0,0 +1,347 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/afoejoe/football-predict/internal/database"
+	"github.com/afoejoe/football-predict/internal/goalmodel"
+	"github.com/afoejoe/football-predict/internal/market"
+	"github.com/afoejoe/football-predict/internal/request"
+	"github.com/afoejoe/football-predict/internal/response"
+	"github.com/afoejoe/football-predict/internal/value"
+)
+
+const (
+	goalModelHistory = 3 * 365 * 24 * time.Hour
+	goalModelTTL     = time.Hour
+)
+
+// fairOddsMarkets are the selections priced for every fixture on the admin
+// prediction form.
+var fairOddsMarkets = []struct {
+	market    market.Market
+	selection string
+	line      float64
+}{
+	{market.MatchResult, market.Home, 0},
+	{market.MatchResult, market.Draw, 0},
+	{market.MatchResult, market.Away, 0},
+	{market.OverUnder, market.Over, 2.5},
+	{market.OverUnder, market.Under, 2.5},
+	{market.BothTeamsToScore, market.Yes, 0},
+	{market.BothTeamsToScore, market.No, 0},
+}
+
+type fairPrice struct {
+	Label       string
+	Probability float64
+	Odds        float64
+}
+
+func (p fairPrice) Percentage() float64 {
+	return p.Probability * 100
+}
+
+type fairOdds struct {
+	Fixture      *database.Fixture
+	HomeExpected float64
+	AwayExpected float64
+	Prices       []fairPrice
+	Selected     *fairPrice
+	Coefficient  float64
+	Message      string
+}
+
+// HasValue reports whether the entered coefficient is longer than the model's
+// fair price for the selection.
+func (f fairOdds) HasValue() bool {
+	return f.Selected != nil && f.Coefficient > f.Selected.Odds
+}
+
+// goalModelFit is the outcome of the last fit, including ErrNoMatches, so that
+// a database without usable results is not queried again on every request.
+type goalModelFit struct {
+	model    *goalmodel.Model
+	err      error
+	fittedAt time.Time
+}
+
+// goalModel returns the model fitted by the fit-goal-model job. It refits when
+// there is no fit yet, which happens on the first call after startup, or when
+// the last fit is older than goalModelTTL, which happens on instances where
+// another instance ran the job. Only one request refits at a time: the first
+// request after startup makes the others wait for its fit, and once there is
+// a fit, requests carry on with the stale one while it is refreshed.
+func (app *application) goalModel() (*goalmodel.Model, error) {
+	fit := app.goalModelCache.Load()
+	if fit != nil && time.Since(fit.fittedAt) <= goalModelTTL {
+		return fit.model, fit.err
+	}
+
+	if fit == nil {
+		app.goalModelMu.Lock()
+	} else if !app.goalModelMu.TryLock() {
+		return fit.model, fit.err
+	}
+	defer app.goalModelMu.Unlock()
+
+	// Another request may have refitted while this one was waiting.
+	if latest := app.goalModelCache.Load(); latest != nil && time.Since(latest.fittedAt) <= goalModelTTL {
+		return latest.model, latest.err
+	}
+
+	err := app.fitGoalModel()
+	if err != nil {
+		return nil, err
+	}
+
+	fit = app.goalModelCache.Load()
+
+	return fit.model, fit.err
+}
+
+// refreshGoalModel refits the model for the fit-goal-model job.
+func (app *application) refreshGoalModel() error {
+	app.goalModelMu.Lock()
+	defer app.goalModelMu.Unlock()
+
+	return app.fitGoalModel()
+}
+
+// fitGoalModel fits the model and caches the result. The caller must hold
+// goalModelMu. Having no results to fit is not an error, as it is expected
+// before the first season is played.
+func (app *application) fitGoalModel() error {
+	now := time.Now()
+
+	results, err := app.db.ListResults(now.Add(-goalModelHistory), now)
+	if err != nil {
+		return err
+	}
+
+	matches := make([]goalmodel.Match, len(results))
+	for i, result := range results {
+		matches[i] = goalmodel.Match{
+			HomeTeam:  result.HomeTeamID,
+			AwayTeam:  result.AwayTeamID,
+			HomeGoals: *result.HomeScore,
+			AwayGoals: *result.AwayScore,
+			PlayedAt:  result.KickoffAt,
+		}
+	}
+
+	model, err := goalmodel.Fit(matches, now, goalmodel.DefaultOptions)
+	if err != nil && !errors.Is(err, goalmodel.ErrNoMatches) {
+		return err
+	}
+
+	app.goalModelCache.Store(&goalModelFit{model: model, err: err, fittedAt: now})
+
+	return nil
+}
+
+func (app *application) fairOddsForFixture(fixture *database.Fixture, m market.Market, selection string, line float64) (fairOdds, error) {
+	result := fairOdds{Fixture: fixture}
+
+	model, err := app.goalModel()
+	if errors.Is(err, goalmodel.ErrNoMatches) {
+		result.Message = "There are no finished fixtures with scores to fit the model to yet."
+		return result, nil
+	}
+	if err != nil {
+		return result, err
+	}
+
+	prediction, ok := model.Predict(fixture.HomeTeamID, fixture.AwayTeamID)
+	if !ok {
+		result.Message = "The model has no results for one of these teams yet."
+		return result, nil
+	}
+
+	result.HomeExpected = prediction.HomeExpected
+	result.AwayExpected = prediction.AwayExpected
+
+	price := func(m market.Market, selection string, line float64) (*fairPrice, error) {
+		odds, ok, err := prediction.FairOdds(m, selection, line)
+		if err != nil || !ok {
+			return nil, err
+		}
+
+		probability, err := prediction.Probability(m, selection, line)
+		if err != nil {
+			return nil, err
+		}
+
+		return &fairPrice{Label: market.Label(m, selection, line), Probability: probability, Odds: odds}, nil
+	}
+
+	for _, f := range fairOddsMarkets {
+		p, err := price(f.market, f.selection, f.line)
+		if err != nil {
+			return result, err
+		}
+		if p != nil {
+			result.Prices = append(result.Prices, *p)
+		}
+	}
+
+	// The selection being edited may be invalid while the editor is still
+	// typing, in which case there is simply no price for it.
+	if m != "" && selection != "" {
+		result.Selected, _ = price(m, selection, line)
+	}
+
+	return result, nil
+}
+
+// adminFairOdds renders the model's fair odds for the fixture and selection in
+// the admin prediction form. The form requests it with htmx whenever a field
+// changes.
+func (app *application) adminFairOdds(w http.ResponseWriter, r *http.Request) {
+	var form predictionForm
+
+	err := request.DecodeQueryString(r, &form)
+	if err != nil {
+		app.badRequest(w, r, err)
+		return
+	}
+
+	var data fairOdds
+
+	if form.FixtureID != 0 {
+		fixture, found, err := app.db.GetFixture(form.FixtureID)
+		if err != nil {
+			app.serverError(w, r, err)
+			return
+		}
+
+		if found {
+			data, err = app.fairOddsForFixture(fixture, form.Market, form.Selection, form.Line)
+			if err != nil {
+				app.serverError(w, r, err)
+				return
+			}
+		}
+	}
+
+	data.Coefficient = form.Coefficient
+
+	err = response.NamedTemplate(w, http.StatusOK, data, "partial:fair-odds", "partials/fair-odds.html")
+	if err != nil {
+		app.serverError(w, r, err)
+	}
+}
+
+// selectionOutcomes returns the goal model's probability of each way that a
+// selection on a fixture that has not been played yet can be settled. It
+// returns false when the fixture has a result, since the model has been fitted
+// to it, or when the model cannot price the selection.
+func (app *application) selectionOutcomes(fixture *database.Fixture, m market.Market, selection string, line float64) (map[market.Outcome]float64, bool, error) {
+	if fixture == nil || fixture.HasResult() {
+		return nil, false, nil
+	}
+
+	model, err := app.goalModel()
+	if errors.Is(err, goalmodel.ErrNoMatches) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	scores, ok := model.Predict(fixture.HomeTeamID, fixture.AwayTeamID)
+	if !ok {
+		return nil, false, nil
+	}
+
+	outcomes, err := scores.Outcomes(m, selection, line)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return outcomes, true, nil
+}
+
+// assessPrediction weighs a prediction's coefficient against the goal model.
+// It returns nil when the model cannot price the prediction, or when the
+// selection can never win.
+func (app *application) assessPrediction(prediction database.Prediction, fixture *database.Fixture) (*value.Assessment, error) {
+	outcomes, ok, err := app.selectionOutcomes(fixture, prediction.Market, prediction.Selection, prediction.Line)
+	if err != nil || !ok {
+		return nil, err
+	}
+
+	probabilities := value.NewProbabilities(outcomes)
+	if probabilities.Win == 0 {
+		return nil, nil
+	}
+
+	assessment := value.Assess(probabilities, prediction.Coefficient, app.valueOptions())
+
+	return &assessment, nil
+}
+
+// recordModelProbability snapshots the goal model's probability for a
+// prediction that has just been saved, so that the model can be audited once
+// the fixture is settled. Saves after kickoff keep the existing snapshot,
+// since by then the model may already know the result. Calibration only
+// grades outright wins and losses, so the snapshot is the chance of an
+// outright win given that the prediction is settled as one or the other.
+func (app *application) recordModelProbability(prediction *database.Prediction) error {
+	var fixture *database.Fixture
+
+	if prediction.FixtureID != nil {
+		var err error
+
+		fixture, _, err = app.db.GetFixture(*prediction.FixtureID)
+		if err != nil {
+			return err
+		}
+
+		if fixture != nil && !time.Now().Before(fixture.KickoffAt) {
+			return nil
+		}
+	}
+
+	var probability *float64
+
+	outcomes, ok, err := app.selectionOutcomes(fixture, prediction.Market, prediction.Selection, prediction.Line)
+	if err != nil {
+		return err
+	}
+
+	if decided := outcomes[market.Won] + outcomes[market.Lost]; ok && decided > 0 {
+		p := outcomes[market.Won] / decided
+		probability = &p
+	}
+
+	prediction.ModelProbability = probability
+
+	return app.db.SetPredictionModelProbability(prediction.ID, probability)
+}
+
+func (app *application) valueOptions() value.Options {
+	return value.Options{
+		Threshold:     app.config.value.threshold,
+		KellyFraction: app.config.value.kellyFraction,
+	}
+}
+
+// tipReferenceOdds returns the goal model's fair odds for a tip, which cap the
+// odds that the tip is scored at on the leaderboard. It returns nil when the
+// model cannot price the tip.
+func (app *application) tipReferenceOdds(fixture *database.Fixture, m market.Market, selection string, line float64) (*float64, error) {
+	outcomes, ok, err := app.selectionOutcomes(fixture, m, selection, line)
+	if err != nil || !ok {
+		return nil, err
+	}
+
+	fairOdds, ok := value.NewProbabilities(outcomes).FairOdds()
+	if !ok {
+		return nil, nil
+	}
+
+	return &fairOdds, nil
+}
@@ -1,38 +1,259 @@
 package main
 
 import (
+	"fmt"
 	"net/http"
 
+	"github.com/afoejoe/football-predict/internal/cookies"
+	"github.com/afoejoe/football-predict/internal/database"
+	"github.com/afoejoe/football-predict/internal/odds"
+	"github.com/afoejoe/football-predict/internal/request"
 	"github.com/afoejoe/football-predict/internal/response"
+	"github.com/afoejoe/football-predict/internal/stats"
+	"github.com/afoejoe/football-predict/internal/validator"
+
+	"github.com/julienschmidt/httprouter"
 )
 
 func (app *application) home(w http.ResponseWriter, r *http.Request) {
+	featured, err := app.db.ListFeaturedPredictions(3)
+	if err != nil {
+		app.serverError(w, r, err)
+		return
+	}
+
+	upcoming, err := app.db.ListUpcomingPredictions(20)
+	if err != nil {
+		app.serverError(w, r, err)
+		return
+	}
+
 	data := app.newTemplateData(r)
+	data["Featured"] = featured
+	data["Upcoming"] = upcoming
 
-	err := response.Page(w, http.StatusOK, data, "pages/home.html")
+	err = response.Page(w, http.StatusOK, data, "pages/home.html")
 	if err != nil {
 		app.serverError(w, r, err)
 	}
 }
 
 func (app *application) single(w http.ResponseWriter, r *http.Request) {
+	slug := httprouter.ParamsFromContext(r.Context()).ByName("slug")
+
+	prediction, found, err := app.db.GetPredictionBySlug(slug)
+	if err != nil {
+		app.serverError(w, r, err)
+		return
+	}
+
+	if !found {
+		app.notFound(w, r)
+		return
+	}
+
+	form := betForm{Odds: prediction.Coefficient}
+
+	if user := contextGetAuthenticatedUser(r); user != nil {
+		bet, found, err := app.db.GetBet(user.ID, prediction.ID)
+		if err != nil {
+			app.serverError(w, r, err)
+			return
+		}
+
+		if found {
+			form = newBetForm(bet, prediction)
+		}
+	}
+
+	app.renderSingle(w, r, http.StatusOK, prediction, form)
+}
+
+func (app *application) renderSingle(w http.ResponseWriter, r *http.Request, status int, prediction *database.Prediction, form betForm) {
+	data := app.newTemplateData(r)
+	data["Prediction"] = prediction
+	data["BetForm"] = form
+
+	if prediction.FixtureID != nil {
+		fixture, found, err := app.db.GetFixture(*prediction.FixtureID)
+		if err != nil {
+			app.serverError(w, r, err)
+			return
+		}
+
+		if found {
+			data["Fixture"] = fixture
+
+			assessment, err := app.assessPrediction(*prediction, fixture)
+			if err != nil {
+				app.serverError(w, r, err)
+				return
+			}
+
+			if assessment != nil {
+				data["Value"] = assessment
+				data["KellyFraction"] = app.config.value.kellyFraction
+			}
+		}
+	}
+
+	err := response.Page(w, status, data, "pages/single.html")
+	if err != nil {
+		app.serverError(w, r, err)
+	}
+}
+
+const searchPageSize = 20
+
+type searchQuery struct {
+	Q         string              `form:"q"`
+	Page      int                 `form:"page"`
+	Validator validator.Validator `form:"-"`
+}
+
+func (app *application) search(w http.ResponseWriter, r *http.Request) {
+	query := searchQuery{Page: 1}
+
+	err := request.DecodeQueryString(r, &query)
+	if err != nil {
+		app.badRequest(w, r, err)
+		return
+	}
+
+	query.Validator.CheckField(validator.Between(query.Page, 1, 10_000), "page", "Page must be between 1 and 10,000")
+	query.Validator.CheckField(validator.MaxRunes(query.Q, 200), "q", "Search must not be more than 200 characters long")
+
 	data := app.newTemplateData(r)
+	data["Query"] = query
+	data["URL"] = r.URL
 
-	err := response.Page(w, http.StatusOK, data, "pages/single.html")
+	if query.Validator.HasErrors() {
+		err := response.Page(w, http.StatusUnprocessableEntity, data, "pages/search.html")
+		if err != nil {
+			app.serverError(w, r, err)
+		}
+		return
+	}
+
+	if validator.NotBlank(query.Q) {
+		results, metadata, err := app.db.SearchPredictions(query.Q, database.Pagination{Page: query.Page, PageSize: searchPageSize})
+		if err != nil {
+			app.serverError(w, r, err)
+			return
+		}
+
+		data["Results"] = results
+		data["Metadata"] = metadata
+	}
+
+	err = response.Page(w, http.StatusOK, data, "pages/search.html")
 	if err != nil {
 		app.serverError(w, r, err)
 	}
 }
 
-func (app *application) admin(w http.ResponseWriter, r *http.Request) {
+func (app *application) trackRecord(w http.ResponseWriter, r *http.Request) {
+	report, err := app.statsReport()
+	if err != nil {
+		app.serverError(w, r, err)
+		return
+	}
+
 	data := app.newTemplateData(r)
+	data["Report"] = report
 
-	err := response.Page(w, http.StatusOK, data, "pages/admin-home.html")
+	err = response.Page(w, http.StatusOK, data, "pages/stats.html")
 	if err != nil {
 		app.serverError(w, r, err)
 	}
 }
 
-// func (app *application) protected(w http.ResponseWriter, r *http.Request) {
-// 	w.Write([]byte("This is a protected handler"))
-// }
+// statsReport returns the report cached by the refresh-stats job, computing it
+// on the first call after startup.
+func (app *application) statsReport() (stats.Report, error) {
+	if report := app.statsCache.Load(); report != nil {
+		return *report, nil
+	}
+
+	return app.computeStatsReport()
+}
+
+func (app *application) refreshStats() error {
+	_, err := app.computeStatsReport()
+	return err
+}
+
+func (app *application) computeStatsReport() (stats.Report, error) {
+	predictions, err := app.db.ListSettledPredictions()
+	if err != nil {
+		return stats.Report{}, err
+	}
+
+	records := make([]stats.Record, len(predictions))
+	for i, prediction := range predictions {
+		competition := "Other"
+		if prediction.CompetitionName != nil {
+			competition = *prediction.CompetitionName
+		}
+
+		records[i] = stats.Record{
+			Competition: competition,
+			Market:      prediction.Market,
+			Odds:        prediction.Coefficient,
+			Outcome:     prediction.Outcome,
+			ScheduledAt: prediction.ScheduledAt,
+		}
+	}
+
+	report := stats.Compute(records)
+	app.statsCache.Store(&report)
+
+	return report, nil
+}
+
+const oddsFormatCookie = "odds_format"
+
+type oddsFormatForm struct {
+	Format   odds.Format `form:"Format"`
+	Redirect string      `form:"Redirect"`
+}
+
+// setOddsFormat stores the visitor's odds format in a signed cookie and sends
+// them back to the page they changed it on.
+func (app *application) setOddsFormat(w http.ResponseWriter, r *http.Request) {
+	var form oddsFormatForm
+
+	err := request.DecodePostForm(r, &form)
+	if err != nil {
+		app.badRequest(w, r, err)
+		return
+	}
+
+	if !validator.In(form.Format, odds.Formats...) {
+		app.badRequest(w, r, fmt.Errorf("invalid odds format %q", form.Format))
+		return
+	}
+
+	cookie := http.Cookie{
+		Name:     oddsFormatCookie,
+		Value:    string(form.Format),
+		Path:     "/",
+		MaxAge:   86400 * 365,
+		HttpOnly: true,
+		Secure:   true,
+		SameSite: http.SameSiteLaxMode,
+	}
+
+	err = cookies.WriteSigned(w, cookie, app.config.cookie.secretKey)
+	if err != nil {
+		app.serverError(w, r, err)
+		return
+	}
+
+	redirectPath := form.Redirect
+	if !isLocalPath(redirectPath) {
+		redirectPath = "/"
+	}
+
+	http.Redirect(w, r, redirectPath, http.StatusSeeOther)
+}
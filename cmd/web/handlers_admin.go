@@ -0,0 +1,324 @@
+package main
+
+import (
+	"net/http"
+	"regexp"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/afoejoe/football-predict/internal/database"
+	"github.com/afoejoe/football-predict/internal/market"
+	"github.com/afoejoe/football-predict/internal/request"
+	"github.com/afoejoe/football-predict/internal/response"
+	"github.com/afoejoe/football-predict/internal/validator"
+)
+
+const dateTimeLocalLayout = "2006-01-02T15:04"
+
+var rgxSlug = regexp.MustCompile("^[a-z0-9]+(?:-[a-z0-9]+)*$")
+
+type predictionForm struct {
+	FixtureID   int                 `form:"FixtureID"`
+	Title       string              `form:"Title"`
+	Slug        string              `form:"Slug"`
+	Keywords    string              `form:"Keywords"`
+	Body        string              `form:"Body"`
+	Market      market.Market       `form:"Market"`
+	Selection   string              `form:"Selection"`
+	Line        float64             `form:"Line"`
+	Coefficient float64             `form:"Coefficient"`
+	Featured    bool                `form:"Featured"`
+	ScheduledAt string              `form:"ScheduledAt"`
+	Validator   validator.Validator `form:"-"`
+}
+
+func newPredictionForm(prediction *database.Prediction) predictionForm {
+	form := predictionForm{
+		Title:       prediction.Title,
+		Slug:        prediction.Slug,
+		Keywords:    prediction.Keywords,
+		Body:        prediction.Body,
+		Market:      prediction.Market,
+		Selection:   prediction.Selection,
+		Line:        prediction.Line,
+		Coefficient: prediction.Coefficient,
+		Featured:    prediction.Featured,
+		ScheduledAt: prediction.ScheduledAt.UTC().Format(dateTimeLocalLayout),
+	}
+
+	if prediction.FixtureID != nil {
+		form.FixtureID = *prediction.FixtureID
+	}
+
+	return form
+}
+
+func (app *application) admin(w http.ResponseWriter, r *http.Request) {
+	predictions, err := app.db.ListLatestPredictions(100)
+	if err != nil {
+		app.serverError(w, r, err)
+		return
+	}
+
+	data := app.newTemplateData(r)
+	data["Predictions"] = predictions
+
+	err = response.Page(w, http.StatusOK, data, "pages/admin-home.html")
+	if err != nil {
+		app.serverError(w, r, err)
+	}
+}
+
+func (app *application) adminCreatePrediction(w http.ResponseWriter, r *http.Request) {
+	form := predictionForm{Market: market.MatchResult}
+
+	switch r.Method {
+	case http.MethodGet:
+		form.ScheduledAt = time.Now().UTC().Truncate(time.Hour).Add(24 * time.Hour).Format(dateTimeLocalLayout)
+
+		app.renderPredictionForm(w, r, http.StatusOK, form, nil)
+
+	case http.MethodPost:
+		err := request.DecodePostForm(r, &form)
+		if err != nil {
+			app.badRequest(w, r, err)
+			return
+		}
+
+		var prediction database.Prediction
+
+		err = app.validatePredictionForm(&form, &prediction)
+		if err != nil {
+			app.serverError(w, r, err)
+			return
+		}
+
+		if form.Validator.HasErrors() {
+			app.renderPredictionForm(w, r, http.StatusUnprocessableEntity, form, nil)
+			return
+		}
+
+		err = app.db.InsertPrediction(&prediction)
+		if err != nil {
+			app.serverError(w, r, err)
+			return
+		}
+
+		err = app.recordModelProbability(&prediction)
+		if err != nil {
+			app.serverError(w, r, err)
+			return
+		}
+
+		http.Redirect(w, r, "/admin", http.StatusSeeOther)
+	}
+}
+
+func (app *application) adminEditPrediction(w http.ResponseWriter, r *http.Request) {
+	id, err := readIDParam(r)
+	if err != nil {
+		app.notFound(w, r)
+		return
+	}
+
+	prediction, found, err := app.db.GetPrediction(id)
+	if err != nil {
+		app.serverError(w, r, err)
+		return
+	}
+
+	if !found {
+		app.notFound(w, r)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		app.renderPredictionForm(w, r, http.StatusOK, newPredictionForm(prediction), prediction)
+
+	case http.MethodPost:
+		var form predictionForm
+
+		err := request.DecodePostForm(r, &form)
+		if err != nil {
+			app.badRequest(w, r, err)
+			return
+		}
+
+		err = app.validatePredictionForm(&form, prediction)
+		if err != nil {
+			app.serverError(w, r, err)
+			return
+		}
+
+		if form.Validator.HasErrors() {
+			app.renderPredictionForm(w, r, http.StatusUnprocessableEntity, form, prediction)
+			return
+		}
+
+		err = app.db.UpdatePrediction(prediction)
+		if err != nil {
+			app.serverError(w, r, err)
+			return
+		}
+
+		err = app.recordModelProbability(prediction)
+		if err != nil {
+			app.serverError(w, r, err)
+			return
+		}
+
+		http.Redirect(w, r, "/admin", http.StatusSeeOther)
+	}
+}
+
+func (app *application) adminDeletePrediction(w http.ResponseWriter, r *http.Request) {
+	id, err := readIDParam(r)
+	if err != nil {
+		app.notFound(w, r)
+		return
+	}
+
+	err = app.db.DeletePrediction(id)
+	if err != nil {
+		app.serverError(w, r, err)
+		return
+	}
+
+	http.Redirect(w, r, "/admin", http.StatusSeeOther)
+}
+
+func (app *application) renderPredictionForm(w http.ResponseWriter, r *http.Request, status int, form predictionForm, prediction *database.Prediction) {
+	fixtures, err := app.db.ListFixtures(time.Now().Add(-7*24*time.Hour), time.Now().Add(90*24*time.Hour))
+	if err != nil {
+		app.serverError(w, r, err)
+		return
+	}
+
+	if form.FixtureID != 0 && !slices.ContainsFunc(fixtures, func(f database.Fixture) bool { return f.ID == form.FixtureID }) {
+		fixture, found, err := app.db.GetFixture(form.FixtureID)
+		if err != nil {
+			app.serverError(w, r, err)
+			return
+		}
+
+		if found {
+			fixtures = append([]database.Fixture{*fixture}, fixtures...)
+		}
+	}
+
+	action := "/admin/predictions/create"
+	if prediction != nil {
+		action = "/admin/predictions/edit/" + strconv.Itoa(prediction.ID)
+	}
+
+	data := app.newTemplateData(r)
+	data["Form"] = form
+	data["Action"] = action
+	data["Prediction"] = prediction
+	data["Fixtures"] = fixtures
+	data["Markets"] = market.Markets
+
+	err = response.Page(w, status, data, "pages/admin-prediction-form.html")
+	if err != nil {
+		app.serverError(w, r, err)
+	}
+}
+
+// validatePredictionForm copies the form values onto the prediction when the form is valid.
+func (app *application) validatePredictionForm(form *predictionForm, prediction *database.Prediction) error {
+	scheduledAt, err := time.ParseInLocation(dateTimeLocalLayout, form.ScheduledAt, time.UTC)
+	form.Validator.CheckField(err == nil, "ScheduledAt", "Date must be a valid date and time")
+
+	if !form.Market.HasLine() {
+		form.Line = 0
+	}
+
+	candidate := *prediction
+	candidate.FixtureID = nil
+	candidate.Title = form.Title
+	candidate.Slug = form.Slug
+	candidate.Keywords = form.Keywords
+	candidate.Body = form.Body
+	candidate.Market = form.Market
+	candidate.Selection = form.Selection
+	candidate.Line = form.Line
+	candidate.Coefficient = form.Coefficient
+	candidate.Featured = form.Featured
+	candidate.ScheduledAt = scheduledAt
+
+	if form.FixtureID != 0 {
+		candidate.FixtureID = &form.FixtureID
+	}
+
+	err = app.checkPrediction(&form.Validator, &candidate)
+	if err != nil {
+		return err
+	}
+
+	if !form.Validator.HasErrors() {
+		*prediction = candidate
+	}
+
+	return nil
+}
+
+// checkPrediction validates a prediction before it is inserted or updated. The
+// field error keys match both the admin form field names and the JSON API keys.
+func (app *application) checkPrediction(v *validator.Validator, prediction *database.Prediction) error {
+	v.CheckField(validator.NotBlank(prediction.Title), "Title", "Title is required")
+	v.CheckField(validator.MaxRunes(prediction.Title, 200), "Title", "Title must not be more than 200 characters long")
+
+	v.CheckField(validator.NotBlank(prediction.Slug), "Slug", "Slug is required")
+	v.CheckField(validator.Matches(prediction.Slug, rgxSlug), "Slug", "Slug must only contain lowercase letters, digits and hyphens")
+
+	v.CheckField(validator.NotBlank(prediction.Body), "Body", "Match details are required")
+
+	checkSelection(v, prediction.Market, prediction.Selection, prediction.Line)
+
+	v.CheckField(validator.Between(prediction.Coefficient, 1.01, 999.99), "Coefficient", "Odds must be between 1.01 and 999.99")
+
+	v.CheckField(!prediction.ScheduledAt.IsZero(), "ScheduledAt", "Date must be provided")
+
+	existing, found, err := app.db.GetPredictionBySlug(prediction.Slug)
+	if err != nil {
+		return err
+	}
+
+	v.CheckField(!found || existing.ID == prediction.ID, "Slug", "Slug is already in use")
+
+	if prediction.FixtureID != nil {
+		_, found, err := app.db.GetFixture(*prediction.FixtureID)
+		if err != nil {
+			return err
+		}
+
+		v.CheckField(found, "FixtureID", "Fixture does not exist")
+	}
+
+	return nil
+}
+
+// checkSelection validates a market, selection and line combination. It is
+// shared by admin predictions and community tips.
+func checkSelection(v *validator.Validator, m market.Market, selection string, line float64) {
+	v.CheckField(validator.In(m, market.Markets...), "Market", "Market is not valid")
+
+	if m == market.CorrectScore {
+		v.CheckField(validator.Matches(selection, market.RgxScore), "Selection", "Selection must be a score such as 2-1")
+	} else {
+		v.CheckField(validator.In(selection, m.Selections()...), "Selection", "Selection is not valid for this market")
+	}
+
+	switch m {
+	case market.OverUnder:
+		v.CheckField(validator.Between(line, 0.25, 20), "Line", "Line must be between 0.25 and 20")
+	case market.AsianHandicap:
+		v.CheckField(validator.Between(line, -10, 10), "Line", "Line must be between -10 and 10")
+	default:
+		v.CheckField(line == 0, "Line", "Line must be 0 for this market")
+	}
+
+	v.CheckField(market.IsQuarterLine(line), "Line", "Line must be a multiple of 0.25")
+}
@@ -0,0 +1,189 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/afoejoe/football-predict/internal/calibration"
+	"github.com/afoejoe/football-predict/internal/database"
+	"github.com/afoejoe/football-predict/internal/market"
+	"github.com/afoejoe/football-predict/internal/request"
+	"github.com/afoejoe/football-predict/internal/response"
+	"github.com/afoejoe/football-predict/internal/validator"
+)
+
+const (
+	calibrationBuckets = 10
+	monthLayout        = "2006-01"
+)
+
+// Probabilities are either implied by the published odds, which include the
+// bookmaker's margin, or the goal model's snapshot from when the prediction
+// was saved.
+const (
+	probabilitySourceImplied = "implied"
+	probabilitySourceModel   = "model"
+)
+
+var probabilitySources = []string{probabilitySourceImplied, probabilitySourceModel}
+
+type analyticsQuery struct {
+	Source    string              `form:"source"`
+	Month     string              `form:"month"`
+	Validator validator.Validator `form:"-"`
+}
+
+type calibrationMonth struct {
+	Month   string
+	Implied calibration.Report
+	Model   calibration.Report
+}
+
+func (app *application) adminAnalytics(w http.ResponseWriter, r *http.Request) {
+	query := analyticsQuery{Source: probabilitySourceImplied}
+
+	err := request.DecodeQueryString(r, &query)
+	if err != nil {
+		app.badRequest(w, r, err)
+		return
+	}
+
+	query.Validator.CheckField(validator.In(query.Source, probabilitySources...), "source", "Source must be implied or model")
+
+	if query.Month != "" {
+		_, err := time.Parse(monthLayout, query.Month)
+		query.Validator.CheckField(err == nil, "month", "Month must be in YYYY-MM format")
+	}
+
+	data := app.newTemplateData(r)
+	data["Query"] = query
+	data["Sources"] = probabilitySources
+	data["URL"] = r.URL
+
+	if query.Validator.HasErrors() {
+		err := response.Page(w, http.StatusUnprocessableEntity, data, "pages/admin-analytics.html")
+		if err != nil {
+			app.serverError(w, r, err)
+		}
+		return
+	}
+
+	predictions, err := app.db.ListSettledPredictions()
+	if err != nil {
+		app.serverError(w, r, err)
+		return
+	}
+
+	var forecasts []calibration.Forecast
+	byMonth := map[string][]database.SettledPrediction{}
+
+	for _, prediction := range predictions {
+		month := prediction.ScheduledAt.UTC().Format(monthLayout)
+		byMonth[month] = append(byMonth[month], prediction)
+
+		if query.Month != "" && month != query.Month {
+			continue
+		}
+
+		forecast, ok := newForecast(prediction.Prediction, query.Source)
+		if ok {
+			forecasts = append(forecasts, forecast)
+		}
+	}
+
+	var months []calibrationMonth
+
+	for month, predictions := range byMonth {
+		months = append(months, calibrationMonth{
+			Month:   month,
+			Implied: computeCalibration(predictions, probabilitySourceImplied),
+			Model:   computeCalibration(predictions, probabilitySourceModel),
+		})
+	}
+
+	sort.Slice(months, func(i, j int) bool {
+		return months[i].Month > months[j].Month
+	})
+
+	report := calibration.Compute(forecasts, calibrationBuckets)
+
+	data["Report"] = report
+	data["Months"] = months
+
+	if report.Forecasts > 0 {
+		data["Chart"] = newCalibrationChart(report)
+	}
+
+	err = response.Page(w, http.StatusOK, data, "pages/admin-analytics.html")
+	if err != nil {
+		app.serverError(w, r, err)
+	}
+}
+
+// newForecast returns the probability that a settled prediction stated, from
+// the given source, and whether it won. Only outright wins and losses are
+// graded: pushes and voids returned the stake, and half outcomes on quarter
+// lines were neither.
+func newForecast(prediction database.Prediction, source string) (calibration.Forecast, bool) {
+	if prediction.Outcome != market.Won && prediction.Outcome != market.Lost {
+		return calibration.Forecast{}, false
+	}
+
+	forecast := calibration.Forecast{Hit: prediction.Outcome == market.Won}
+
+	switch source {
+	case probabilitySourceImplied:
+		forecast.Probability = 1 / prediction.Coefficient
+	case probabilitySourceModel:
+		if prediction.ModelProbability == nil {
+			return calibration.Forecast{}, false
+		}
+		forecast.Probability = *prediction.ModelProbability
+	}
+
+	return forecast, true
+}
+
+func computeCalibration(predictions []database.SettledPrediction, source string) calibration.Report {
+	var forecasts []calibration.Forecast
+
+	for _, prediction := range predictions {
+		forecast, ok := newForecast(prediction.Prediction, source)
+		if ok {
+			forecasts = append(forecasts, forecast)
+		}
+	}
+
+	return calibration.Compute(forecasts, calibrationBuckets)
+}
+
+// newCalibrationChart plots the hit rate against the mean stated probability
+// for each bucket that has forecasts in it. Points on the diagonal are
+// perfectly calibrated; points below it were overconfident.
+func newCalibrationChart(report calibration.Report) lineChart {
+	var points []chartPoint
+
+	for _, bucket := range report.Buckets {
+		if bucket.Forecasts == 0 {
+			continue
+		}
+
+		points = append(points, chartPoint{
+			X:     bucket.MeanProbability,
+			Y:     bucket.HitRate,
+			Label: fmt.Sprintf("%.0f–%.0f%%: %d predictions, stated %.1f%%, won %.1f%%", bucket.Lower*100, bucket.Upper*100, bucket.Forecasts, bucket.MeanProbability*100, bucket.HitRate*100),
+		})
+	}
+
+	axis := chartAxis{Min: 0, Max: 1}
+	for v := 0; v <= 100; v += 20 {
+		axis.Ticks = append(axis.Ticks, chartTick{Position: float64(v) / 100, Label: fmt.Sprintf("%d%%", v)})
+	}
+
+	chart := newLineChart(points, axis, axis)
+	chart.Diagonal = true
+
+	return chart
+}
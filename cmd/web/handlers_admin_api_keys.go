@@ -0,0 +1,116 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/afoejoe/football-predict/internal/database"
+	"github.com/afoejoe/football-predict/internal/request"
+	"github.com/afoejoe/football-predict/internal/response"
+	"github.com/afoejoe/football-predict/internal/token"
+	"github.com/afoejoe/football-predict/internal/validator"
+)
+
+const apiKeyPrefixLength = 8
+
+type apiKeyForm struct {
+	Owner      string              `form:"Owner"`
+	Scopes     []string            `form:"Scopes"`
+	DailyQuota int                 `form:"DailyQuota"`
+	Validator  validator.Validator `form:"-"`
+}
+
+func (app *application) adminAPIKeys(w http.ResponseWriter, r *http.Request) {
+	form := apiKeyForm{Scopes: []string{database.ScopeRead}, DailyQuota: 1000}
+
+	switch r.Method {
+	case http.MethodGet:
+		app.renderAdminAPIKeys(w, r, http.StatusOK, form, "")
+
+	case http.MethodPost:
+		form.Scopes = nil
+
+		err := request.DecodePostForm(r, &form)
+		if err != nil {
+			app.badRequest(w, r, err)
+			return
+		}
+
+		form.Validator.CheckField(validator.NotBlank(form.Owner), "Owner", "Owner is required")
+		form.Validator.CheckField(validator.MaxRunes(form.Owner, 200), "Owner", "Owner must not be more than 200 characters long")
+		form.Validator.CheckField(len(form.Scopes) > 0, "Scopes", "At least one scope is required")
+		form.Validator.CheckField(validator.AllIn(form.Scopes, database.Scopes...), "Scopes", "Scopes are not valid")
+		form.Validator.CheckField(validator.NoDuplicates(form.Scopes), "Scopes", "Scopes must not contain duplicates")
+		form.Validator.CheckField(validator.Between(form.DailyQuota, 1, 1_000_000), "DailyQuota", "Daily quota must be between 1 and 1,000,000")
+
+		if form.Validator.HasErrors() {
+			app.renderAdminAPIKeys(w, r, http.StatusUnprocessableEntity, form, "")
+			return
+		}
+
+		plaintext, err := token.New()
+		if err != nil {
+			app.serverError(w, r, err)
+			return
+		}
+
+		key := database.APIKey{
+			Owner:      form.Owner,
+			Prefix:     plaintext[:apiKeyPrefixLength],
+			Hash:       token.Hash(plaintext),
+			Scopes:     form.Scopes,
+			DailyQuota: form.DailyQuota,
+		}
+
+		err = app.db.InsertAPIKey(&key)
+		if err != nil {
+			app.serverError(w, r, err)
+			return
+		}
+
+		// The plaintext key is only ever shown on this response, so it is
+		// rendered directly rather than after a redirect.
+		app.renderAdminAPIKeys(w, r, http.StatusCreated, apiKeyForm{Scopes: []string{database.ScopeRead}, DailyQuota: 1000}, plaintext)
+	}
+}
+
+func (app *application) adminRevokeAPIKey(w http.ResponseWriter, r *http.Request) {
+	id, err := readIDParam(r)
+	if err != nil {
+		app.notFound(w, r)
+		return
+	}
+
+	err = app.db.RevokeAPIKey(id)
+	if err != nil {
+		app.serverError(w, r, err)
+		return
+	}
+
+	http.Redirect(w, r, "/admin/api-keys", http.StatusSeeOther)
+}
+
+func (app *application) renderAdminAPIKeys(w http.ResponseWriter, r *http.Request, status int, form apiKeyForm, plaintext string) {
+	keys, err := app.db.ListAPIKeys()
+	if err != nil {
+		app.serverError(w, r, err)
+		return
+	}
+
+	usage, err := app.db.APIKeyUsageToday()
+	if err != nil {
+		app.serverError(w, r, err)
+		return
+	}
+
+	data := app.newTemplateData(r)
+	data["Form"] = form
+	data["APIKeys"] = keys
+	data["Usage"] = usage
+	data["Scopes"] = database.Scopes
+	data["NewKey"] = plaintext
+
+	err = response.Page(w, status, data, "pages/admin-api-keys.html")
+	if err != nil {
+		app.serverError(w, r, err)
+	}
+}
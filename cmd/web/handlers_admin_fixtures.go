@@ -0,0 +1,736 @@
+package main
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/afoejoe/football-predict/internal/database"
+	"github.com/afoejoe/football-predict/internal/request"
+	"github.com/afoejoe/football-predict/internal/response"
+	"github.com/afoejoe/football-predict/internal/validator"
+)
+
+const dateLayout = "2006-01-02"
+
+type teamForm struct {
+	Name      string              `form:"Name"`
+	Slug      string              `form:"Slug"`
+	ShortName string              `form:"ShortName"`
+	Country   string              `form:"Country"`
+	Validator validator.Validator `form:"-"`
+}
+
+type competitionForm struct {
+	Name      string              `form:"Name"`
+	Slug      string              `form:"Slug"`
+	Country   string              `form:"Country"`
+	Validator validator.Validator `form:"-"`
+}
+
+type seasonForm struct {
+	CompetitionID int                 `form:"CompetitionID"`
+	Name          string              `form:"Name"`
+	StartsOn      string              `form:"StartsOn"`
+	EndsOn        string              `form:"EndsOn"`
+	Validator     validator.Validator `form:"-"`
+}
+
+type fixtureForm struct {
+	SeasonID   int                 `form:"SeasonID"`
+	HomeTeamID int                 `form:"HomeTeamID"`
+	AwayTeamID int                 `form:"AwayTeamID"`
+	Venue      string              `form:"Venue"`
+	KickoffAt  string              `form:"KickoffAt"`
+	Status     string              `form:"Status"`
+	HomeScore  string              `form:"HomeScore"`
+	AwayScore  string              `form:"AwayScore"`
+	Validator  validator.Validator `form:"-"`
+}
+
+func (app *application) adminTeams(w http.ResponseWriter, r *http.Request) {
+	var form teamForm
+
+	switch r.Method {
+	case http.MethodGet:
+		app.renderAdminTeams(w, r, http.StatusOK, form, nil)
+
+	case http.MethodPost:
+		err := request.DecodePostForm(r, &form)
+		if err != nil {
+			app.badRequest(w, r, err)
+			return
+		}
+
+		var team database.Team
+
+		err = app.validateTeamForm(&form, &team)
+		if err != nil {
+			app.serverError(w, r, err)
+			return
+		}
+
+		if form.Validator.HasErrors() {
+			app.renderAdminTeams(w, r, http.StatusUnprocessableEntity, form, nil)
+			return
+		}
+
+		err = app.db.InsertTeam(&team)
+		if err != nil {
+			app.serverError(w, r, err)
+			return
+		}
+
+		http.Redirect(w, r, "/admin/teams", http.StatusSeeOther)
+	}
+}
+
+func (app *application) adminEditTeam(w http.ResponseWriter, r *http.Request) {
+	id, err := readIDParam(r)
+	if err != nil {
+		app.notFound(w, r)
+		return
+	}
+
+	team, found, err := app.db.GetTeam(id)
+	if err != nil {
+		app.serverError(w, r, err)
+		return
+	}
+
+	if !found {
+		app.notFound(w, r)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		form := teamForm{Name: team.Name, Slug: team.Slug, ShortName: team.ShortName, Country: team.Country}
+		app.renderAdminTeams(w, r, http.StatusOK, form, team)
+
+	case http.MethodPost:
+		var form teamForm
+
+		err := request.DecodePostForm(r, &form)
+		if err != nil {
+			app.badRequest(w, r, err)
+			return
+		}
+
+		err = app.validateTeamForm(&form, team)
+		if err != nil {
+			app.serverError(w, r, err)
+			return
+		}
+
+		if form.Validator.HasErrors() {
+			app.renderAdminTeams(w, r, http.StatusUnprocessableEntity, form, team)
+			return
+		}
+
+		err = app.db.UpdateTeam(team)
+		if err != nil {
+			app.serverError(w, r, err)
+			return
+		}
+
+		http.Redirect(w, r, "/admin/teams", http.StatusSeeOther)
+	}
+}
+
+func (app *application) renderAdminTeams(w http.ResponseWriter, r *http.Request, status int, form teamForm, team *database.Team) {
+	teams, err := app.db.ListTeams()
+	if err != nil {
+		app.serverError(w, r, err)
+		return
+	}
+
+	action := "/admin/teams"
+	if team != nil {
+		action = "/admin/teams/edit/" + strconv.Itoa(team.ID)
+	}
+
+	data := app.newTemplateData(r)
+	data["Teams"] = teams
+	data["Team"] = team
+	data["Form"] = form
+	data["Action"] = action
+
+	err = response.Page(w, status, data, "pages/admin-teams.html")
+	if err != nil {
+		app.serverError(w, r, err)
+	}
+}
+
+func (app *application) validateTeamForm(form *teamForm, team *database.Team) error {
+	form.Validator.CheckField(validator.NotBlank(form.Name), "Name", "Name is required")
+	form.Validator.CheckField(validator.MaxRunes(form.Name, 100), "Name", "Name must not be more than 100 characters long")
+	form.Validator.CheckField(validator.Matches(form.Slug, rgxSlug), "Slug", "Slug must only contain lowercase letters, digits and hyphens")
+	form.Validator.CheckField(validator.MaxRunes(form.ShortName, 10), "ShortName", "Short name must not be more than 10 characters long")
+
+	existing, found, err := app.db.GetTeamBySlug(form.Slug)
+	if err != nil {
+		return err
+	}
+
+	form.Validator.CheckField(!found || existing.ID == team.ID, "Slug", "Slug is already in use")
+
+	if form.Validator.HasErrors() {
+		return nil
+	}
+
+	team.Name = form.Name
+	team.Slug = form.Slug
+	team.ShortName = form.ShortName
+	team.Country = form.Country
+
+	return nil
+}
+
+func (app *application) adminCompetitions(w http.ResponseWriter, r *http.Request) {
+	var form competitionForm
+
+	switch r.Method {
+	case http.MethodGet:
+		app.renderAdminCompetitions(w, r, http.StatusOK, form, nil)
+
+	case http.MethodPost:
+		err := request.DecodePostForm(r, &form)
+		if err != nil {
+			app.badRequest(w, r, err)
+			return
+		}
+
+		var competition database.Competition
+
+		err = app.validateCompetitionForm(&form, &competition)
+		if err != nil {
+			app.serverError(w, r, err)
+			return
+		}
+
+		if form.Validator.HasErrors() {
+			app.renderAdminCompetitions(w, r, http.StatusUnprocessableEntity, form, nil)
+			return
+		}
+
+		err = app.db.InsertCompetition(&competition)
+		if err != nil {
+			app.serverError(w, r, err)
+			return
+		}
+
+		http.Redirect(w, r, "/admin/competitions", http.StatusSeeOther)
+	}
+}
+
+func (app *application) adminEditCompetition(w http.ResponseWriter, r *http.Request) {
+	id, err := readIDParam(r)
+	if err != nil {
+		app.notFound(w, r)
+		return
+	}
+
+	competition, found, err := app.db.GetCompetition(id)
+	if err != nil {
+		app.serverError(w, r, err)
+		return
+	}
+
+	if !found {
+		app.notFound(w, r)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		form := competitionForm{Name: competition.Name, Slug: competition.Slug, Country: competition.Country}
+		app.renderAdminCompetitions(w, r, http.StatusOK, form, competition)
+
+	case http.MethodPost:
+		var form competitionForm
+
+		err := request.DecodePostForm(r, &form)
+		if err != nil {
+			app.badRequest(w, r, err)
+			return
+		}
+
+		err = app.validateCompetitionForm(&form, competition)
+		if err != nil {
+			app.serverError(w, r, err)
+			return
+		}
+
+		if form.Validator.HasErrors() {
+			app.renderAdminCompetitions(w, r, http.StatusUnprocessableEntity, form, competition)
+			return
+		}
+
+		err = app.db.UpdateCompetition(competition)
+		if err != nil {
+			app.serverError(w, r, err)
+			return
+		}
+
+		http.Redirect(w, r, "/admin/competitions", http.StatusSeeOther)
+	}
+}
+
+func (app *application) renderAdminCompetitions(w http.ResponseWriter, r *http.Request, status int, form competitionForm, competition *database.Competition) {
+	competitions, err := app.db.ListCompetitions()
+	if err != nil {
+		app.serverError(w, r, err)
+		return
+	}
+
+	action := "/admin/competitions"
+	if competition != nil {
+		action = "/admin/competitions/edit/" + strconv.Itoa(competition.ID)
+	}
+
+	data := app.newTemplateData(r)
+	data["Competitions"] = competitions
+	data["Competition"] = competition
+	data["Form"] = form
+	data["Action"] = action
+
+	err = response.Page(w, status, data, "pages/admin-competitions.html")
+	if err != nil {
+		app.serverError(w, r, err)
+	}
+}
+
+func (app *application) validateCompetitionForm(form *competitionForm, competition *database.Competition) error {
+	form.Validator.CheckField(validator.NotBlank(form.Name), "Name", "Name is required")
+	form.Validator.CheckField(validator.MaxRunes(form.Name, 100), "Name", "Name must not be more than 100 characters long")
+	form.Validator.CheckField(validator.Matches(form.Slug, rgxSlug), "Slug", "Slug must only contain lowercase letters, digits and hyphens")
+
+	existing, found, err := app.db.GetCompetitionBySlug(form.Slug)
+	if err != nil {
+		return err
+	}
+
+	form.Validator.CheckField(!found || existing.ID == competition.ID, "Slug", "Slug is already in use")
+
+	if form.Validator.HasErrors() {
+		return nil
+	}
+
+	competition.Name = form.Name
+	competition.Slug = form.Slug
+	competition.Country = form.Country
+
+	return nil
+}
+
+func (app *application) adminSeasons(w http.ResponseWriter, r *http.Request) {
+	var form seasonForm
+
+	switch r.Method {
+	case http.MethodGet:
+		app.renderAdminSeasons(w, r, http.StatusOK, form, nil)
+
+	case http.MethodPost:
+		err := request.DecodePostForm(r, &form)
+		if err != nil {
+			app.badRequest(w, r, err)
+			return
+		}
+
+		var season database.Season
+
+		err = app.validateSeasonForm(&form, &season)
+		if err != nil {
+			app.serverError(w, r, err)
+			return
+		}
+
+		if form.Validator.HasErrors() {
+			app.renderAdminSeasons(w, r, http.StatusUnprocessableEntity, form, nil)
+			return
+		}
+
+		err = app.db.InsertSeason(&season)
+		if err != nil {
+			app.serverError(w, r, err)
+			return
+		}
+
+		http.Redirect(w, r, "/admin/seasons", http.StatusSeeOther)
+	}
+}
+
+func (app *application) adminEditSeason(w http.ResponseWriter, r *http.Request) {
+	id, err := readIDParam(r)
+	if err != nil {
+		app.notFound(w, r)
+		return
+	}
+
+	season, found, err := app.db.GetSeason(id)
+	if err != nil {
+		app.serverError(w, r, err)
+		return
+	}
+
+	if !found {
+		app.notFound(w, r)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		form := seasonForm{
+			CompetitionID: season.CompetitionID,
+			Name:          season.Name,
+			StartsOn:      season.StartsOn.Format(dateLayout),
+			EndsOn:        season.EndsOn.Format(dateLayout),
+		}
+		app.renderAdminSeasons(w, r, http.StatusOK, form, season)
+
+	case http.MethodPost:
+		var form seasonForm
+
+		err := request.DecodePostForm(r, &form)
+		if err != nil {
+			app.badRequest(w, r, err)
+			return
+		}
+
+		err = app.validateSeasonForm(&form, season)
+		if err != nil {
+			app.serverError(w, r, err)
+			return
+		}
+
+		if form.Validator.HasErrors() {
+			app.renderAdminSeasons(w, r, http.StatusUnprocessableEntity, form, season)
+			return
+		}
+
+		err = app.db.UpdateSeason(season)
+		if err != nil {
+			app.serverError(w, r, err)
+			return
+		}
+
+		http.Redirect(w, r, "/admin/seasons", http.StatusSeeOther)
+	}
+}
+
+func (app *application) renderAdminSeasons(w http.ResponseWriter, r *http.Request, status int, form seasonForm, season *database.Season) {
+	seasons, err := app.db.ListSeasons()
+	if err != nil {
+		app.serverError(w, r, err)
+		return
+	}
+
+	competitions, err := app.db.ListCompetitions()
+	if err != nil {
+		app.serverError(w, r, err)
+		return
+	}
+
+	action := "/admin/seasons"
+	if season != nil {
+		action = "/admin/seasons/edit/" + strconv.Itoa(season.ID)
+	}
+
+	data := app.newTemplateData(r)
+	data["Seasons"] = seasons
+	data["Season"] = season
+	data["Competitions"] = competitions
+	data["Form"] = form
+	data["Action"] = action
+
+	err = response.Page(w, status, data, "pages/admin-seasons.html")
+	if err != nil {
+		app.serverError(w, r, err)
+	}
+}
+
+func (app *application) validateSeasonForm(form *seasonForm, season *database.Season) error {
+	_, found, err := app.db.GetCompetition(form.CompetitionID)
+	if err != nil {
+		return err
+	}
+
+	form.Validator.CheckField(found, "CompetitionID", "Competition does not exist")
+	form.Validator.CheckField(validator.NotBlank(form.Name), "Name", "Name is required")
+	form.Validator.CheckField(validator.MaxRunes(form.Name, 50), "Name", "Name must not be more than 50 characters long")
+
+	startsOn, err := time.Parse(dateLayout, form.StartsOn)
+	form.Validator.CheckField(err == nil, "StartsOn", "Start date must be a valid date")
+
+	endsOn, err := time.Parse(dateLayout, form.EndsOn)
+	form.Validator.CheckField(err == nil, "EndsOn", "End date must be a valid date")
+	form.Validator.CheckField(!endsOn.Before(startsOn), "EndsOn", "End date must not be before the start date")
+
+	if form.Validator.HasErrors() {
+		return nil
+	}
+
+	season.CompetitionID = form.CompetitionID
+	season.Name = form.Name
+	season.StartsOn = startsOn
+	season.EndsOn = endsOn
+
+	return nil
+}
+
+func (app *application) adminFixtures(w http.ResponseWriter, r *http.Request) {
+	form := fixtureForm{Status: database.FixtureStatusScheduled}
+
+	switch r.Method {
+	case http.MethodGet:
+		form.KickoffAt = time.Now().UTC().Truncate(time.Hour).Add(24 * time.Hour).Format(dateTimeLocalLayout)
+
+		app.renderAdminFixtures(w, r, http.StatusOK, form, nil)
+
+	case http.MethodPost:
+		err := request.DecodePostForm(r, &form)
+		if err != nil {
+			app.badRequest(w, r, err)
+			return
+		}
+
+		var fixture database.Fixture
+
+		err = app.validateFixtureForm(&form, &fixture)
+		if err != nil {
+			app.serverError(w, r, err)
+			return
+		}
+
+		if form.Validator.HasErrors() {
+			app.renderAdminFixtures(w, r, http.StatusUnprocessableEntity, form, nil)
+			return
+		}
+
+		err = app.db.InsertFixture(&fixture)
+		if err != nil {
+			app.serverError(w, r, err)
+			return
+		}
+
+		http.Redirect(w, r, "/admin/fixtures", http.StatusSeeOther)
+	}
+}
+
+func (app *application) adminEditFixture(w http.ResponseWriter, r *http.Request) {
+	id, err := readIDParam(r)
+	if err != nil {
+		app.notFound(w, r)
+		return
+	}
+
+	fixture, found, err := app.db.GetFixture(id)
+	if err != nil {
+		app.serverError(w, r, err)
+		return
+	}
+
+	if !found {
+		app.notFound(w, r)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		form := fixtureForm{
+			SeasonID:   fixture.SeasonID,
+			HomeTeamID: fixture.HomeTeamID,
+			AwayTeamID: fixture.AwayTeamID,
+			Venue:      fixture.Venue,
+			KickoffAt:  fixture.KickoffAt.UTC().Format(dateTimeLocalLayout),
+			Status:     fixture.Status,
+		}
+
+		if fixture.HasResult() {
+			form.HomeScore = strconv.Itoa(*fixture.HomeScore)
+			form.AwayScore = strconv.Itoa(*fixture.AwayScore)
+		}
+
+		app.renderAdminFixtures(w, r, http.StatusOK, form, fixture)
+
+	case http.MethodPost:
+		var form fixtureForm
+
+		err := request.DecodePostForm(r, &form)
+		if err != nil {
+			app.badRequest(w, r, err)
+			return
+		}
+
+		err = app.validateFixtureForm(&form, fixture)
+		if err != nil {
+			app.serverError(w, r, err)
+			return
+		}
+
+		if form.Validator.HasErrors() {
+			app.renderAdminFixtures(w, r, http.StatusUnprocessableEntity, form, fixture)
+			return
+		}
+
+		err = app.db.UpdateFixture(fixture)
+		if err != nil {
+			app.serverError(w, r, err)
+			return
+		}
+
+		app.backgroundTask(r, func() error {
+			return app.settleFixture(fixture.ID)
+		})
+
+		http.Redirect(w, r, "/admin/fixtures", http.StatusSeeOther)
+	}
+}
+
+func (app *application) adminSettleFixture(w http.ResponseWriter, r *http.Request) {
+	id, err := readIDParam(r)
+	if err != nil {
+		app.notFound(w, r)
+		return
+	}
+
+	err = app.settleFixture(id)
+	if err != nil {
+		app.serverError(w, r, err)
+		return
+	}
+
+	http.Redirect(w, r, "/admin/fixtures", http.StatusSeeOther)
+}
+
+func (app *application) adminSettlePredictions(w http.ResponseWriter, r *http.Request) {
+	app.backgroundTask(r, app.settlePredictions)
+
+	http.Redirect(w, r, "/admin/fixtures", http.StatusSeeOther)
+}
+
+func (app *application) adminDeleteFixture(w http.ResponseWriter, r *http.Request) {
+	id, err := readIDParam(r)
+	if err != nil {
+		app.notFound(w, r)
+		return
+	}
+
+	err = app.db.DeleteFixture(id)
+	if err != nil {
+		app.serverError(w, r, err)
+		return
+	}
+
+	app.backgroundTask(r, app.recomputeRatings)
+
+	http.Redirect(w, r, "/admin/fixtures", http.StatusSeeOther)
+}
+
+func (app *application) renderAdminFixtures(w http.ResponseWriter, r *http.Request, status int, form fixtureForm, fixture *database.Fixture) {
+	fixtures, err := app.db.ListFixtures(time.Now().Add(-30*24*time.Hour), time.Now().Add(180*24*time.Hour))
+	if err != nil {
+		app.serverError(w, r, err)
+		return
+	}
+
+	seasons, err := app.db.ListSeasons()
+	if err != nil {
+		app.serverError(w, r, err)
+		return
+	}
+
+	teams, err := app.db.ListTeams()
+	if err != nil {
+		app.serverError(w, r, err)
+		return
+	}
+
+	action := "/admin/fixtures"
+	if fixture != nil {
+		action = "/admin/fixtures/edit/" + strconv.Itoa(fixture.ID)
+	}
+
+	data := app.newTemplateData(r)
+	data["Fixtures"] = fixtures
+	data["Fixture"] = fixture
+	data["Seasons"] = seasons
+	data["Teams"] = teams
+	data["Statuses"] = database.FixtureStatuses
+	data["Form"] = form
+	data["Action"] = action
+
+	err = response.Page(w, status, data, "pages/admin-fixtures.html")
+	if err != nil {
+		app.serverError(w, r, err)
+	}
+}
+
+func (app *application) validateFixtureForm(form *fixtureForm, fixture *database.Fixture) error {
+	_, found, err := app.db.GetSeason(form.SeasonID)
+	if err != nil {
+		return err
+	}
+
+	form.Validator.CheckField(found, "SeasonID", "Season does not exist")
+
+	_, found, err = app.db.GetTeam(form.HomeTeamID)
+	if err != nil {
+		return err
+	}
+
+	form.Validator.CheckField(found, "HomeTeamID", "Home team does not exist")
+
+	_, found, err = app.db.GetTeam(form.AwayTeamID)
+	if err != nil {
+		return err
+	}
+
+	form.Validator.CheckField(found, "AwayTeamID", "Away team does not exist")
+	form.Validator.CheckField(form.HomeTeamID != form.AwayTeamID, "AwayTeamID", "Away team must be different from the home team")
+
+	kickoffAt, err := time.ParseInLocation(dateTimeLocalLayout, form.KickoffAt, time.UTC)
+	form.Validator.CheckField(err == nil, "KickoffAt", "Kickoff must be a valid date and time")
+
+	form.Validator.CheckField(validator.In(form.Status, database.FixtureStatuses...), "Status", "Status is not valid")
+
+	homeScore, ok := parseScore(form.HomeScore)
+	form.Validator.CheckField(ok, "HomeScore", "Home score must be a whole number between 0 and 99")
+
+	awayScore, ok := parseScore(form.AwayScore)
+	form.Validator.CheckField(ok, "AwayScore", "Away score must be a whole number between 0 and 99")
+
+	form.Validator.CheckField((homeScore == nil) == (awayScore == nil), "AwayScore", "Both scores must be entered together")
+	form.Validator.CheckField(form.Status != database.FixtureStatusFinished || homeScore != nil, "HomeScore", "A finished fixture must have a final score")
+
+	if form.Validator.HasErrors() {
+		return nil
+	}
+
+	fixture.SeasonID = form.SeasonID
+	fixture.HomeTeamID = form.HomeTeamID
+	fixture.AwayTeamID = form.AwayTeamID
+	fixture.Venue = form.Venue
+	fixture.KickoffAt = kickoffAt
+	fixture.Status = form.Status
+	fixture.HomeScore = homeScore
+	fixture.AwayScore = awayScore
+
+	return nil
+}
+
+func parseScore(s string) (*int, bool) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, true
+	}
+
+	n, err := strconv.Atoi(s)
+	if err != nil || !validator.Between(n, 0, 99) {
+		return nil, false
+	}
+
+	return &n, true
+}
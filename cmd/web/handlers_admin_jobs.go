@@ -0,0 +1,73 @@
+package main
+
+import (
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/afoejoe/football-predict/internal/database"
+	"github.com/afoejoe/football-predict/internal/response"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+const adminJobRunsLimit = 50
+
+type jobStatus struct {
+	Name     string
+	Schedule string
+	NextRun  time.Time
+	LastRun  *database.JobRun
+}
+
+func (app *application) adminJobs(w http.ResponseWriter, r *http.Request) {
+	runs, err := app.db.ListJobRuns(adminJobRunsLimit)
+	if err != nil {
+		app.serverError(w, r, err)
+		return
+	}
+
+	var statuses []jobStatus
+
+	for _, j := range app.jobs() {
+		status := jobStatus{
+			Name:     j.name,
+			Schedule: j.schedule.String(),
+			NextRun:  j.schedule.Next(time.Now().UTC()),
+		}
+
+		for i := range runs {
+			if runs[i].Name == j.name {
+				status.LastRun = &runs[i]
+				break
+			}
+		}
+
+		statuses = append(statuses, status)
+	}
+
+	data := app.newTemplateData(r)
+	data["Jobs"] = statuses
+	data["Runs"] = runs
+	data["Queued"] = r.URL.Query().Get("queued")
+
+	err = response.Page(w, http.StatusOK, data, "pages/admin-jobs.html")
+	if err != nil {
+		app.serverError(w, r, err)
+	}
+}
+
+func (app *application) adminRunJob(w http.ResponseWriter, r *http.Request) {
+	j, found := app.findJob(httprouter.ParamsFromContext(r.Context()).ByName("name"))
+	if !found {
+		app.notFound(w, r)
+		return
+	}
+
+	app.backgroundTask(r, func() error {
+		app.runJob(j, time.Now().UTC())
+		return nil
+	})
+
+	http.Redirect(w, r, "/admin/jobs?queued="+url.QueryEscape(j.name), http.StatusSeeOther)
+}
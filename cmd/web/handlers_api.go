@@ -0,0 +1,412 @@
+package main
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/afoejoe/football-predict/internal/database"
+	"github.com/afoejoe/football-predict/internal/market"
+	"github.com/afoejoe/football-predict/internal/request"
+	"github.com/afoejoe/football-predict/internal/response"
+	"github.com/afoejoe/football-predict/internal/validator"
+	"github.com/afoejoe/football-predict/internal/value"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+func (app *application) searchJSON(w http.ResponseWriter, r *http.Request) {
+	query := searchQuery{Page: 1}
+
+	err := request.DecodeQueryString(r, &query)
+	if err != nil {
+		app.badRequestJSON(w, r, err)
+		return
+	}
+
+	query.Validator.CheckField(validator.NotBlank(query.Q), "q", "Search must be provided")
+	query.Validator.CheckField(validator.MaxRunes(query.Q, 200), "q", "Search must not be more than 200 characters long")
+	query.Validator.CheckField(validator.Between(query.Page, 1, 10_000), "page", "Page must be between 1 and 10,000")
+
+	if query.Validator.HasErrors() {
+		app.failedValidationJSON(w, r, query.Validator)
+		return
+	}
+
+	results, metadata, err := app.db.SearchPredictions(query.Q, database.Pagination{Page: query.Page, PageSize: searchPageSize})
+	if err != nil {
+		app.serverErrorJSON(w, r, err)
+		return
+	}
+
+	if results == nil {
+		results = []database.SearchResult{}
+	}
+
+	err = response.JSON(w, http.StatusOK, map[string]any{"Results": results, "Metadata": metadata})
+	if err != nil {
+		app.serverErrorJSON(w, r, err)
+	}
+}
+
+func (app *application) statsJSON(w http.ResponseWriter, r *http.Request) {
+	report, err := app.statsReport()
+	if err != nil {
+		app.serverErrorJSON(w, r, err)
+		return
+	}
+
+	err = response.JSON(w, http.StatusOK, report)
+	if err != nil {
+		app.serverErrorJSON(w, r, err)
+	}
+}
+
+const (
+	defaultAPIPageSize = 20
+	maxAPIPageSize     = 100
+)
+
+// apiPrediction is the JSON representation of a prediction, with derived
+// fields that clients would otherwise have to work out themselves. Value is
+// null unless the goal model can price a fixture that has not been played.
+type apiPrediction struct {
+	database.Prediction
+	Label string
+	Value *value.Assessment
+}
+
+// apiPredictions converts predictions to their JSON representation, looking
+// up each fixture once.
+func (app *application) apiPredictions(predictions ...database.Prediction) ([]apiPrediction, error) {
+	fixtures := map[int]*database.Fixture{}
+	results := make([]apiPrediction, 0, len(predictions))
+
+	for _, prediction := range predictions {
+		result := apiPrediction{Prediction: prediction, Label: prediction.Label()}
+
+		if prediction.FixtureID != nil {
+			fixture, ok := fixtures[*prediction.FixtureID]
+			if !ok {
+				var err error
+
+				fixture, _, err = app.db.GetFixture(*prediction.FixtureID)
+				if err != nil {
+					return nil, err
+				}
+
+				fixtures[*prediction.FixtureID] = fixture
+			}
+
+			assessment, err := app.assessPrediction(prediction, fixture)
+			if err != nil {
+				return nil, err
+			}
+
+			result.Value = assessment
+		}
+
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+type predictionsQuery struct {
+	Competition string              `form:"competition"`
+	Market      string              `form:"market"`
+	Outcome     string              `form:"outcome"`
+	Featured    *bool               `form:"featured"`
+	From        string              `form:"from"`
+	To          string              `form:"to"`
+	Sort        string              `form:"sort"`
+	Page        int                 `form:"page"`
+	PageSize    int                 `form:"page_size"`
+	Validator   validator.Validator `form:"-"`
+}
+
+func (app *application) listPredictionsJSON(w http.ResponseWriter, r *http.Request) {
+	query := predictionsQuery{Page: 1, PageSize: defaultAPIPageSize, Sort: "-scheduled_at"}
+
+	err := request.DecodeQueryString(r, &query)
+	if err != nil {
+		app.badRequestJSON(w, r, err)
+		return
+	}
+
+	filter := database.PredictionFilter{
+		Competition: query.Competition,
+		Market:      market.Market(query.Market),
+		Outcome:     market.Outcome(query.Outcome),
+		Featured:    query.Featured,
+		Sort:        query.Sort,
+	}
+
+	if query.Market != "" {
+		query.Validator.CheckField(validator.In(filter.Market, market.Markets...), "market", "Market is not valid")
+	}
+
+	if query.Outcome != "" {
+		query.Validator.CheckField(validator.In(filter.Outcome, market.Outcomes...), "outcome", "Outcome is not valid")
+	}
+
+	if query.From != "" {
+		from, err := time.ParseInLocation(dateLayout, query.From, time.UTC)
+		query.Validator.CheckField(err == nil, "from", "From must be a date in YYYY-MM-DD format")
+		filter.From = &from
+	}
+
+	if query.To != "" {
+		to, err := time.ParseInLocation(dateLayout, query.To, time.UTC)
+		query.Validator.CheckField(err == nil, "to", "To must be a date in YYYY-MM-DD format")
+		to = to.AddDate(0, 0, 1)
+		filter.To = &to
+	}
+
+	query.Validator.CheckField(validator.In(query.Sort, database.PredictionSortValues...), "sort", "Sort is not valid")
+	query.Validator.CheckField(validator.Between(query.Page, 1, 10_000), "page", "Page must be between 1 and 10,000")
+	query.Validator.CheckField(validator.Between(query.PageSize, 1, maxAPIPageSize), "page_size", "Page size must be between 1 and 100")
+
+	if query.Validator.HasErrors() {
+		app.failedValidationJSON(w, r, query.Validator)
+		return
+	}
+
+	predictions, metadata, err := app.db.FilterPredictions(filter, database.Pagination{Page: query.Page, PageSize: query.PageSize})
+	if err != nil {
+		app.serverErrorJSON(w, r, err)
+		return
+	}
+
+	results, err := app.apiPredictions(predictions...)
+	if err != nil {
+		app.serverErrorJSON(w, r, err)
+		return
+	}
+
+	err = response.JSON(w, http.StatusOK, map[string]any{"Predictions": results, "Metadata": metadata})
+	if err != nil {
+		app.serverErrorJSON(w, r, err)
+	}
+}
+
+func (app *application) getPredictionJSON(w http.ResponseWriter, r *http.Request) {
+	slug := httprouter.ParamsFromContext(r.Context()).ByName("slug")
+
+	prediction, found, err := app.db.GetPredictionBySlug(slug)
+	if err != nil {
+		app.serverErrorJSON(w, r, err)
+		return
+	}
+
+	if !found {
+		app.notFoundJSON(w, r)
+		return
+	}
+
+	results, err := app.apiPredictions(*prediction)
+	if err != nil {
+		app.serverErrorJSON(w, r, err)
+		return
+	}
+
+	err = response.JSON(w, http.StatusOK, map[string]any{"Prediction": results[0]})
+	if err != nil {
+		app.serverErrorJSON(w, r, err)
+	}
+}
+
+// predictionInput is the request body for creating and updating predictions.
+// Fields are pointers so that a PATCH only changes the keys that were sent. A
+// FixtureID of 0 detaches the prediction from its fixture.
+type predictionInput struct {
+	FixtureID   *int
+	Title       *string
+	Slug        *string
+	Keywords    *string
+	Body        *string
+	Market      *market.Market
+	Selection   *string
+	Line        *float64
+	Coefficient *float64
+	Featured    *bool
+	ScheduledAt *time.Time
+}
+
+func (input predictionInput) apply(prediction *database.Prediction) {
+	if input.FixtureID != nil {
+		prediction.FixtureID = nil
+		if *input.FixtureID != 0 {
+			prediction.FixtureID = input.FixtureID
+		}
+	}
+	if input.Title != nil {
+		prediction.Title = *input.Title
+	}
+	if input.Slug != nil {
+		prediction.Slug = *input.Slug
+	}
+	if input.Keywords != nil {
+		prediction.Keywords = *input.Keywords
+	}
+	if input.Body != nil {
+		prediction.Body = *input.Body
+	}
+	if input.Market != nil {
+		prediction.Market = *input.Market
+	}
+	if input.Selection != nil {
+		prediction.Selection = *input.Selection
+	}
+	if input.Line != nil {
+		prediction.Line = *input.Line
+	}
+	if input.Coefficient != nil {
+		prediction.Coefficient = *input.Coefficient
+	}
+	if input.Featured != nil {
+		prediction.Featured = *input.Featured
+	}
+	if input.ScheduledAt != nil {
+		prediction.ScheduledAt = input.ScheduledAt.UTC()
+	}
+}
+
+func (app *application) createPredictionJSON(w http.ResponseWriter, r *http.Request) {
+	var input predictionInput
+
+	err := request.DecodeJSONStrict(w, r, &input)
+	if err != nil {
+		app.badRequestJSON(w, r, err)
+		return
+	}
+
+	prediction := &database.Prediction{Outcome: market.Pending}
+	input.apply(prediction)
+
+	var v validator.Validator
+
+	err = app.checkPrediction(&v, prediction)
+	if err != nil {
+		app.serverErrorJSON(w, r, err)
+		return
+	}
+
+	if v.HasErrors() {
+		app.failedValidationJSON(w, r, v)
+		return
+	}
+
+	err = app.db.InsertPrediction(prediction)
+	if err != nil {
+		app.serverErrorJSON(w, r, err)
+		return
+	}
+
+	err = app.recordModelProbability(prediction)
+	if err != nil {
+		app.serverErrorJSON(w, r, err)
+		return
+	}
+
+	headers := make(http.Header)
+	headers.Set("Location", "/api/v1/predictions/"+prediction.Slug)
+
+	results, err := app.apiPredictions(*prediction)
+	if err != nil {
+		app.serverErrorJSON(w, r, err)
+		return
+	}
+
+	err = response.JSONWithHeaders(w, http.StatusCreated, map[string]any{"Prediction": results[0]}, headers)
+	if err != nil {
+		app.serverErrorJSON(w, r, err)
+	}
+}
+
+func (app *application) updatePredictionJSON(w http.ResponseWriter, r *http.Request) {
+	slug := httprouter.ParamsFromContext(r.Context()).ByName("slug")
+
+	prediction, found, err := app.db.GetPredictionBySlug(slug)
+	if err != nil {
+		app.serverErrorJSON(w, r, err)
+		return
+	}
+
+	if !found {
+		app.notFoundJSON(w, r)
+		return
+	}
+
+	var input predictionInput
+
+	err = request.DecodeJSONStrict(w, r, &input)
+	if err != nil {
+		app.badRequestJSON(w, r, err)
+		return
+	}
+
+	input.apply(prediction)
+
+	if !prediction.Market.HasLine() && input.Line == nil {
+		prediction.Line = 0
+	}
+
+	var v validator.Validator
+
+	err = app.checkPrediction(&v, prediction)
+	if err != nil {
+		app.serverErrorJSON(w, r, err)
+		return
+	}
+
+	if v.HasErrors() {
+		app.failedValidationJSON(w, r, v)
+		return
+	}
+
+	err = app.db.UpdatePrediction(prediction)
+	if err != nil {
+		app.serverErrorJSON(w, r, err)
+		return
+	}
+
+	err = app.recordModelProbability(prediction)
+	if err != nil {
+		app.serverErrorJSON(w, r, err)
+		return
+	}
+
+	results, err := app.apiPredictions(*prediction)
+	if err != nil {
+		app.serverErrorJSON(w, r, err)
+		return
+	}
+
+	err = response.JSON(w, http.StatusOK, map[string]any{"Prediction": results[0]})
+	if err != nil {
+		app.serverErrorJSON(w, r, err)
+	}
+}
+
+func (app *application) deletePredictionJSON(w http.ResponseWriter, r *http.Request) {
+	slug := httprouter.ParamsFromContext(r.Context()).ByName("slug")
+
+	prediction, found, err := app.db.GetPredictionBySlug(slug)
+	if err != nil {
+		app.serverErrorJSON(w, r, err)
+		return
+	}
+
+	if !found {
+		app.notFoundJSON(w, r)
+		return
+	}
+
+	err = app.db.DeletePrediction(prediction.ID)
+	if err != nil {
+		app.serverErrorJSON(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
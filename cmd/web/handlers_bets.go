@@ -0,0 +1,181 @@
+package main
+
+import (
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/afoejoe/football-predict/internal/database"
+	"github.com/afoejoe/football-predict/internal/request"
+	"github.com/afoejoe/football-predict/internal/response"
+	"github.com/afoejoe/football-predict/internal/stats"
+	"github.com/afoejoe/football-predict/internal/validator"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+type betForm struct {
+	Following bool                `form:"-"`
+	Stake     float64             `form:"Stake"`
+	Odds      float64             `form:"Odds"`
+	Validator validator.Validator `form:"-"`
+}
+
+func newBetForm(bet *database.Bet, prediction *database.Prediction) betForm {
+	form := betForm{Following: true, Stake: bet.Stake, Odds: prediction.Coefficient}
+
+	if bet.Odds != nil {
+		form.Odds = *bet.Odds
+	}
+
+	return form
+}
+
+func (app *application) followPrediction(w http.ResponseWriter, r *http.Request) {
+	prediction, found, err := app.db.GetPredictionBySlug(httprouter.ParamsFromContext(r.Context()).ByName("slug"))
+	if err != nil {
+		app.serverError(w, r, err)
+		return
+	}
+
+	if !found {
+		app.notFound(w, r)
+		return
+	}
+
+	var form betForm
+
+	err = request.DecodePostForm(r, &form)
+	if err != nil {
+		app.badRequest(w, r, err)
+		return
+	}
+
+	form.Validator.CheckField(validator.Between(form.Stake, 0, 1_000_000), "Stake", "Stake must be between 0 and 1,000,000")
+
+	if form.Stake > 0 {
+		form.Validator.CheckField(validator.Between(form.Odds, 1.01, 999.99), "Odds", "Odds must be between 1.01 and 999.99")
+	}
+
+	if form.Validator.HasErrors() {
+		app.renderSingle(w, r, http.StatusUnprocessableEntity, prediction, form)
+		return
+	}
+
+	bet := database.Bet{
+		UserID:       contextGetAuthenticatedUser(r).ID,
+		PredictionID: prediction.ID,
+		Stake:        form.Stake,
+	}
+
+	if form.Stake > 0 {
+		bet.Odds = &form.Odds
+	}
+
+	err = app.db.SaveBet(&bet)
+	if err != nil {
+		app.serverError(w, r, err)
+		return
+	}
+
+	http.Redirect(w, r, "/prediction/"+prediction.Slug, http.StatusSeeOther)
+}
+
+func (app *application) unfollowPrediction(w http.ResponseWriter, r *http.Request) {
+	prediction, found, err := app.db.GetPredictionBySlug(httprouter.ParamsFromContext(r.Context()).ByName("slug"))
+	if err != nil {
+		app.serverError(w, r, err)
+		return
+	}
+
+	if !found {
+		app.notFound(w, r)
+		return
+	}
+
+	err = app.db.DeleteBet(contextGetAuthenticatedUser(r).ID, prediction.ID)
+	if err != nil {
+		app.serverError(w, r, err)
+		return
+	}
+
+	redirect := "/prediction/" + prediction.Slug
+	if r.URL.Query().Get("from") == "profile" {
+		redirect = "/profile"
+	}
+
+	http.Redirect(w, r, redirect, http.StatusSeeOther)
+}
+
+func (app *application) profileBetsJSON(w http.ResponseWriter, r *http.Request) {
+	bets, summary, err := app.userBets(contextGetAuthenticatedUser(r).ID)
+	if err != nil {
+		app.serverErrorJSON(w, r, err)
+		return
+	}
+
+	type jsonBet struct {
+		apiPrediction
+		Stake      float64
+		Odds       *float64
+		Profit     float64
+		FollowedAt time.Time
+	}
+
+	predictions := make([]database.Prediction, len(bets))
+	for i, bet := range bets {
+		predictions[i] = bet.Prediction
+	}
+
+	apiPredictions, err := app.apiPredictions(predictions...)
+	if err != nil {
+		app.serverErrorJSON(w, r, err)
+		return
+	}
+
+	results := make([]jsonBet, 0, len(bets))
+	for i, bet := range bets {
+		results = append(results, jsonBet{
+			apiPrediction: apiPredictions[i],
+			Stake:         bet.Stake,
+			Odds:          bet.Odds,
+			Profit:        bet.Profit(),
+			FollowedAt:    bet.FollowedAt,
+		})
+	}
+
+	err = response.JSON(w, http.StatusOK, map[string]any{"Bets": results, "Summary": summary})
+	if err != nil {
+		app.serverErrorJSON(w, r, err)
+	}
+}
+
+// userBets returns every prediction the user follows, along with a summary of
+// the settled ones that they recorded a stake on.
+func (app *application) userBets(userID int) ([]database.UserBet, stats.Summary, error) {
+	bets, err := app.db.ListUserBets(userID)
+	if err != nil {
+		return nil, stats.Summary{}, err
+	}
+
+	var records []stats.Record
+	for _, bet := range bets {
+		if !bet.Staked() || !bet.Outcome.Settled() {
+			continue
+		}
+
+		records = append(records, stats.Record{
+			Market:      bet.Market,
+			Odds:        *bet.Odds,
+			Stake:       bet.Stake,
+			Outcome:     bet.Outcome,
+			ScheduledAt: bet.ScheduledAt,
+		})
+	}
+
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].ScheduledAt.Before(records[j].ScheduledAt)
+	})
+
+	return bets, stats.Summarize(records), nil
+}
@@ -0,0 +1,216 @@
+package main
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/afoejoe/football-predict/internal/database"
+	"github.com/afoejoe/football-predict/internal/request"
+	"github.com/afoejoe/football-predict/internal/response"
+	"github.com/afoejoe/football-predict/internal/signing"
+	"github.com/afoejoe/football-predict/internal/validator"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+const (
+	signingNameNewsletterConfirm     = "newsletter-confirm"
+	signingNameNewsletterUnsubscribe = "newsletter-unsubscribe"
+
+	// Confirmation links expire quickly, as a leaked one would otherwise sign
+	// its owner up for good. Unsubscribe links go out with every digest, so
+	// they can last much longer.
+	newsletterConfirmTTL     = 72 * time.Hour
+	newsletterUnsubscribeTTL = 365 * 24 * time.Hour
+
+	// newsletterConfirmInterval is the least time between two confirmation
+	// emails to the same address.
+	newsletterConfirmInterval = 15 * time.Minute
+)
+
+type newsletterForm struct {
+	Email     string              `form:"Email"`
+	Validator validator.Validator `form:"-"`
+}
+
+func (app *application) newsletter(w http.ResponseWriter, r *http.Request) {
+	var form newsletterForm
+
+	data := app.newTemplateData(r)
+
+	switch r.Method {
+	case http.MethodGet:
+		data["Form"] = form
+
+		err := response.Page(w, http.StatusOK, data, "pages/newsletter.html")
+		if err != nil {
+			app.serverError(w, r, err)
+		}
+
+	case http.MethodPost:
+		err := request.DecodePostForm(r, &form)
+		if err != nil {
+			app.badRequest(w, r, err)
+			return
+		}
+
+		form.Email = strings.TrimSpace(form.Email)
+
+		form.Validator.CheckField(validator.NotBlank(form.Email), "Email", "Email is required")
+		form.Validator.CheckField(validator.IsEmail(form.Email), "Email", "Must be a valid email address")
+
+		data["Form"] = form
+
+		if form.Validator.HasErrors() {
+			err := response.Page(w, http.StatusUnprocessableEntity, data, "pages/newsletter.html")
+			if err != nil {
+				app.serverError(w, r, err)
+			}
+			return
+		}
+
+		subscriber, err := app.db.UpsertSubscriber(form.Email)
+		if err != nil {
+			app.serverError(w, r, err)
+			return
+		}
+
+		// Existing subscribers, and addresses that were sent a confirmation
+		// email recently, get the same response without another email. That way
+		// the form cannot be used to find out who is on the list or to flood
+		// someone's inbox.
+		if !subscriber.Active() {
+			claimed, err := app.db.ClaimConfirmationEmail(subscriber.ID, newsletterConfirmInterval)
+			if err != nil {
+				app.serverError(w, r, err)
+				return
+			}
+
+			if claimed {
+				app.backgroundTask(r, func() error {
+					emailData := app.newEmailData()
+					emailData["ConfirmURL"] = app.newsletterURL("confirm", signingNameNewsletterConfirm, subscriber.ID)
+					emailData["TTL"] = newsletterConfirmTTL
+
+					return app.mailer.Send(subscriber.Email, emailData, "newsletter-confirm.html")
+				})
+			}
+		}
+
+		data["Sent"] = true
+
+		err = response.Page(w, http.StatusOK, data, "pages/newsletter.html")
+		if err != nil {
+			app.serverError(w, r, err)
+		}
+	}
+}
+
+func (app *application) newsletterConfirm(w http.ResponseWriter, r *http.Request) {
+	subscriber, found, err := app.subscriberFromSignature(r, signingNameNewsletterConfirm, newsletterConfirmTTL)
+	if err != nil {
+		app.serverError(w, r, err)
+		return
+	}
+
+	data := app.newTemplateData(r)
+	data["InvalidLink"] = !found
+
+	if found {
+		err = app.db.ConfirmSubscriber(subscriber.ID)
+		if err != nil {
+			app.serverError(w, r, err)
+			return
+		}
+
+		data["Confirmed"] = true
+	}
+
+	err = response.Page(w, http.StatusOK, data, "pages/newsletter.html")
+	if err != nil {
+		app.serverError(w, r, err)
+	}
+}
+
+// newsletterUnsubscribe shows a confirmation button on GET, so that link
+// scanners cannot unsubscribe people, and unsubscribes on POST. The POST also
+// serves one-click unsubscribes from mail clients that support the
+// List-Unsubscribe-Post header.
+func (app *application) newsletterUnsubscribe(w http.ResponseWriter, r *http.Request) {
+	subscriber, found, err := app.subscriberFromSignature(r, signingNameNewsletterUnsubscribe, newsletterUnsubscribeTTL)
+	if err != nil {
+		app.serverError(w, r, err)
+		return
+	}
+
+	data := app.newTemplateData(r)
+	data["InvalidLink"] = !found
+	data["UnsubscribeURL"] = r.URL.Path
+
+	if found && r.Method == http.MethodPost {
+		err = app.db.UnsubscribeSubscriber(subscriber.ID)
+		if err != nil {
+			app.serverError(w, r, err)
+			return
+		}
+
+		data["Unsubscribed"] = true
+	}
+
+	err = response.Page(w, http.StatusOK, data, "pages/newsletter.html")
+	if err != nil {
+		app.serverError(w, r, err)
+	}
+}
+
+func (app *application) adminNewsletter(w http.ResponseWriter, r *http.Request) {
+	counts, err := app.db.CountSubscribers()
+	if err != nil {
+		app.serverError(w, r, err)
+		return
+	}
+
+	outbox, err := app.db.CountOutboxEmails()
+	if err != nil {
+		app.serverError(w, r, err)
+		return
+	}
+
+	data := app.newTemplateData(r)
+	data["Counts"] = counts
+	data["Outbox"] = outbox
+	data["DigestQueued"] = r.URL.Query().Get("digest") == "queued"
+
+	err = response.Page(w, http.StatusOK, data, "pages/admin-newsletter.html")
+	if err != nil {
+		app.serverError(w, r, err)
+	}
+}
+
+func (app *application) adminSendDigest(w http.ResponseWriter, r *http.Request) {
+	app.backgroundTask(r, app.sendDigest)
+
+	http.Redirect(w, r, "/admin/newsletter?digest=queued", http.StatusSeeOther)
+}
+
+// subscriberFromSignature returns the subscriber in a signed newsletter link.
+// It returns false if the link is invalid or older than maxAge.
+func (app *application) subscriberFromSignature(r *http.Request, name string, maxAge time.Duration) (*database.Subscriber, bool, error) {
+	value, err := signing.VerifyWithMaxAge(name, httprouter.ParamsFromContext(r.Context()).ByName("signature"), app.config.cookie.secretKey, maxAge, time.Now())
+	if err != nil {
+		return nil, false, nil
+	}
+
+	id, err := strconv.Atoi(value)
+	if err != nil {
+		return nil, false, nil
+	}
+
+	return app.db.GetSubscriber(id)
+}
+
+func (app *application) newsletterURL(action, signingName string, subscriberID int) string {
+	return app.config.baseURL + "/newsletter/" + action + "/" + signing.SignWithTime(signingName, strconv.Itoa(subscriberID), app.config.cookie.secretKey, time.Now())
+}
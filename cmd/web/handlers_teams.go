@@ -0,0 +1,88 @@
+package main
+
+import (
+	"math"
+	"net/http"
+
+	"github.com/afoejoe/football-predict/internal/database"
+	"github.com/afoejoe/football-predict/internal/elo"
+	"github.com/afoejoe/football-predict/internal/response"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+const teamRecentFixtures = 10
+
+func (app *application) team(w http.ResponseWriter, r *http.Request) {
+	slug := httprouter.ParamsFromContext(r.Context()).ByName("slug")
+
+	team, found, err := app.db.GetTeamBySlug(slug)
+	if err != nil {
+		app.serverError(w, r, err)
+		return
+	}
+
+	if !found {
+		app.notFound(w, r)
+		return
+	}
+
+	ratings, err := app.db.ListTeamRatings(team.ID)
+	if err != nil {
+		app.serverError(w, r, err)
+		return
+	}
+
+	fixtures, err := app.db.ListTeamFixtures(team.ID, teamRecentFixtures)
+	if err != nil {
+		app.serverError(w, r, err)
+		return
+	}
+
+	data := app.newTemplateData(r)
+	data["Team"] = team
+	data["Rating"] = elo.DefaultOptions.Initial
+	data["Ratings"] = ratings
+	data["Fixtures"] = fixtures
+
+	if len(ratings) > 0 {
+		data["Rating"] = ratings[len(ratings)-1].RatingAfter
+		data["Chart"] = newRatingChart(ratings)
+	}
+
+	err = response.Page(w, http.StatusOK, data, "pages/team.html")
+	if err != nil {
+		app.serverError(w, r, err)
+	}
+}
+
+// newRatingChart plots a team's rating after each match, starting from its
+// rating before the first one. Matches are spaced evenly rather than by date,
+// so that breaks between seasons do not flatten the line.
+func newRatingChart(ratings []database.TeamRating) lineChart {
+	points := []chartPoint{{X: 0, Y: ratings[0].RatingBefore, Label: "Initial rating"}}
+
+	low, high := ratings[0].RatingBefore, ratings[0].RatingBefore
+
+	for i, rating := range ratings {
+		points = append(points, chartPoint{
+			X:     float64(i + 1),
+			Y:     rating.RatingAfter,
+			Label: rating.KickoffAt.Format("02/01/2006") + " vs " + rating.OpponentName,
+		})
+
+		low = math.Min(low, rating.RatingAfter)
+		high = math.Max(high, rating.RatingAfter)
+	}
+
+	x := chartAxis{
+		Min: 0,
+		Max: float64(len(ratings)),
+		Ticks: []chartTick{
+			{Position: 1, Label: ratings[0].KickoffAt.Format("Jan 2006")},
+			{Position: float64(len(ratings)), Label: ratings[len(ratings)-1].KickoffAt.Format("Jan 2006")},
+		},
+	}
+
+	return newLineChart(points, x, niceAxis(low, high, "%.0f"))
+}
@@ -0,0 +1,313 @@
+package main
+
+import (
+	"net/http"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/afoejoe/football-predict/internal/database"
+	"github.com/afoejoe/football-predict/internal/market"
+	"github.com/afoejoe/football-predict/internal/request"
+	"github.com/afoejoe/football-predict/internal/response"
+	"github.com/afoejoe/football-predict/internal/stats"
+	"github.com/afoejoe/football-predict/internal/validator"
+)
+
+const (
+	tipsLookahead = 14 * 24 * time.Hour
+
+	// maxTipOdds caps the odds that any tip is scored at, including tips that
+	// the goal model could not price.
+	maxTipOdds = 10.0
+)
+
+// leaderboardWindows maps each leaderboard window to how far back it looks and
+// the minimum number of settled tips needed to be ranked in it.
+var leaderboardWindows = map[string]struct {
+	Period  time.Duration
+	MinTips int
+}{
+	"week":  {7 * 24 * time.Hour, 5},
+	"month": {30 * 24 * time.Hour, 15},
+	"all":   {0, 30},
+}
+
+type tipForm struct {
+	Market    market.Market       `form:"Market"`
+	Selection string              `form:"Selection"`
+	Line      float64             `form:"Line"`
+	Odds      float64             `form:"Odds"`
+	Validator validator.Validator `form:"-"`
+}
+
+func (app *application) tips(w http.ResponseWriter, r *http.Request) {
+	fixtures, err := app.db.ListFixtures(time.Now(), time.Now().Add(tipsLookahead))
+	if err != nil {
+		app.serverError(w, r, err)
+		return
+	}
+
+	fixtures = slices.DeleteFunc(fixtures, func(f database.Fixture) bool {
+		return f.Status != database.FixtureStatusScheduled
+	})
+
+	tips, err := app.db.ListUserTips(contextGetAuthenticatedUser(r).ID, 50)
+	if err != nil {
+		app.serverError(w, r, err)
+		return
+	}
+
+	tipped := map[int]bool{}
+	for _, tip := range tips {
+		tipped[tip.FixtureID] = true
+	}
+
+	data := app.newTemplateData(r)
+	data["Fixtures"] = fixtures
+	data["Tips"] = tips
+	data["Tipped"] = tipped
+	data["Now"] = time.Now()
+
+	err = response.Page(w, http.StatusOK, data, "pages/tips.html")
+	if err != nil {
+		app.serverError(w, r, err)
+	}
+}
+
+func (app *application) tipFixture(w http.ResponseWriter, r *http.Request) {
+	id, err := readIDParam(r)
+	if err != nil {
+		app.notFound(w, r)
+		return
+	}
+
+	fixture, found, err := app.db.GetFixture(id)
+	if err != nil {
+		app.serverError(w, r, err)
+		return
+	}
+
+	if !found {
+		app.notFound(w, r)
+		return
+	}
+
+	user := contextGetAuthenticatedUser(r)
+
+	tip, found, err := app.db.GetUserFixtureTip(user.ID, fixture.ID)
+	if err != nil {
+		app.serverError(w, r, err)
+		return
+	}
+
+	form := tipForm{Market: market.MatchResult}
+	if found {
+		form = tipForm{Market: tip.Market, Selection: tip.Selection, Line: tip.Line, Odds: tip.Odds}
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		app.renderTipForm(w, r, http.StatusOK, fixture, tip, form)
+
+	case http.MethodPost:
+		form = tipForm{}
+
+		err := request.DecodePostForm(r, &form)
+		if err != nil {
+			app.badRequest(w, r, err)
+			return
+		}
+
+		if !form.Market.HasLine() {
+			form.Line = 0
+		}
+
+		checkSelection(&form.Validator, form.Market, form.Selection, form.Line)
+		form.Validator.CheckField(validator.Between(form.Odds, 1.01, 999.99), "Odds", "Odds must be between 1.01 and 999.99")
+
+		if form.Validator.HasErrors() {
+			app.renderTipForm(w, r, http.StatusUnprocessableEntity, fixture, tip, form)
+			return
+		}
+
+		referenceOdds, err := app.tipReferenceOdds(fixture, form.Market, form.Selection, form.Line)
+		if err != nil {
+			app.serverError(w, r, err)
+			return
+		}
+
+		saved, err := app.db.SaveTip(&database.Tip{
+			UserID:        user.ID,
+			FixtureID:     fixture.ID,
+			Market:        form.Market,
+			Selection:     form.Selection,
+			Line:          form.Line,
+			Odds:          form.Odds,
+			ReferenceOdds: referenceOdds,
+		})
+		if err != nil {
+			app.serverError(w, r, err)
+			return
+		}
+
+		if !saved {
+			form.Validator.AddError("Tips are locked once the fixture kicks off")
+			app.renderTipForm(w, r, http.StatusUnprocessableEntity, fixture, tip, form)
+			return
+		}
+
+		http.Redirect(w, r, "/tips", http.StatusSeeOther)
+	}
+}
+
+func (app *application) deleteTip(w http.ResponseWriter, r *http.Request) {
+	id, err := readIDParam(r)
+	if err != nil {
+		app.notFound(w, r)
+		return
+	}
+
+	_, err = app.db.DeleteTip(contextGetAuthenticatedUser(r).ID, id)
+	if err != nil {
+		app.serverError(w, r, err)
+		return
+	}
+
+	http.Redirect(w, r, "/tips", http.StatusSeeOther)
+}
+
+func (app *application) renderTipForm(w http.ResponseWriter, r *http.Request, status int, fixture *database.Fixture, tip *database.Tip, form tipForm) {
+	data := app.newTemplateData(r)
+	data["Fixture"] = fixture
+	data["Tip"] = tip
+	data["Form"] = form
+	data["Markets"] = market.Markets
+	data["Locked"] = fixture.Status != database.FixtureStatusScheduled || !fixture.KickoffAt.After(time.Now())
+
+	err := response.Page(w, status, data, "pages/tip-form.html")
+	if err != nil {
+		app.serverError(w, r, err)
+	}
+}
+
+type leaderboardEntry struct {
+	Rank     int
+	UserName string
+	stats.Summary
+}
+
+func (app *application) leaderboard(w http.ResponseWriter, r *http.Request) {
+	window := r.URL.Query().Get("window")
+	if _, ok := leaderboardWindows[window]; !ok {
+		window = "month"
+	}
+
+	sortBy := r.URL.Query().Get("sort")
+	if sortBy != "roi" {
+		sortBy = "profit"
+	}
+
+	entries, err := app.leaderboardEntries(window, sortBy)
+	if err != nil {
+		app.serverError(w, r, err)
+		return
+	}
+
+	data := app.newTemplateData(r)
+	data["Entries"] = entries
+	data["Window"] = window
+	data["Sort"] = sortBy
+	data["MinTips"] = leaderboardWindows[window].MinTips
+	data["MaxTipOdds"] = maxTipOdds
+
+	err = response.Page(w, http.StatusOK, data, "pages/leaderboard.html")
+	if err != nil {
+		app.serverError(w, r, err)
+	}
+}
+
+// leaderboardEntries ranks tipsters on their settled tips in a window.
+func (app *application) leaderboardEntries(window, sortBy string) ([]leaderboardEntry, error) {
+	var since *time.Time
+
+	if period := leaderboardWindows[window].Period; period > 0 {
+		t := time.Now().Add(-period)
+		since = &t
+	}
+
+	tips, err := app.db.ListSettledTips(since)
+	if err != nil {
+		return nil, err
+	}
+
+	return rankTipsters(tips, leaderboardWindows[window].MinTips, sortBy), nil
+}
+
+// rankTipsters ranks tipsters on their settled tips at a flat one unit stake
+// and at their scored odds. Tipsters with fewer settled tips than minTips are
+// left out so that a couple of lucky long shots cannot top the table.
+func rankTipsters(tips []database.SettledTip, minTips int, sortBy string) []leaderboardEntry {
+	records := map[int][]stats.Record{}
+	names := map[int]string{}
+
+	for _, tip := range tips {
+		names[tip.UserID] = tip.UserName
+		records[tip.UserID] = append(records[tip.UserID], stats.Record{
+			Market:      tip.Market,
+			Odds:        scoredOdds(tip.Tip),
+			Outcome:     tip.Outcome,
+			ScheduledAt: tip.KickoffAt,
+		})
+	}
+
+	var entries []leaderboardEntry
+
+	for userID, userRecords := range records {
+		summary := stats.Summarize(userRecords)
+
+		if summary.Predictions < minTips {
+			continue
+		}
+
+		entries = append(entries, leaderboardEntry{UserName: names[userID], Summary: summary})
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+
+		if sortBy == "roi" && a.Yield != b.Yield {
+			return a.Yield > b.Yield
+		}
+
+		if a.Profit != b.Profit {
+			return a.Profit > b.Profit
+		}
+
+		if a.Yield != b.Yield {
+			return a.Yield > b.Yield
+		}
+
+		return a.UserName < b.UserName
+	})
+
+	for i := range entries {
+		entries[i].Rank = i + 1
+	}
+
+	return entries
+}
+
+// scoredOdds returns the odds that a tip counts at on the leaderboard. Tipsters
+// enter their own odds, so they are capped at the goal model's fair odds from
+// when the tip was saved and at maxTipOdds. Otherwise anyone could top the
+// table by claiming a long price on a favourite.
+func scoredOdds(tip database.Tip) float64 {
+	odds := min(tip.Odds, maxTipOdds)
+
+	if tip.ReferenceOdds != nil {
+		odds = min(odds, *tip.ReferenceOdds)
+	}
+
+	return odds
+}
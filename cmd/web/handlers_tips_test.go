@@ -0,0 +1,87 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/afoejoe/football-predict/internal/database"
+	"github.com/afoejoe/football-predict/internal/market"
+)
+
+func TestRankTipstersIgnoresInflatedOdds(t *testing.T) {
+	kickoff := time.Date(2024, 3, 2, 15, 0, 0, 0, time.UTC)
+
+	newTip := func(userID int, userName string, odds, referenceOdds float64, outcome market.Outcome) database.SettledTip {
+		return database.SettledTip{
+			Tip: database.Tip{
+				UserID:        userID,
+				Market:        market.MatchResult,
+				Selection:     market.Home,
+				Odds:          odds,
+				ReferenceOdds: &referenceOdds,
+				Outcome:       outcome,
+			},
+			UserName:  userName,
+			KickoffAt: kickoff,
+		}
+	}
+
+	// Alice wins three of four at fair odds of 2.00. Bob backs favourites
+	// priced at 1.50 by the model and wins two of four.
+	tips := func(bobOdds float64) []database.SettledTip {
+		return []database.SettledTip{
+			newTip(1, "Alice", 2, 2, market.Won),
+			newTip(1, "Alice", 2, 2, market.Won),
+			newTip(1, "Alice", 2, 2, market.Won),
+			newTip(1, "Alice", 2, 2, market.Lost),
+			newTip(2, "Bob", bobOdds, 1.5, market.Won),
+			newTip(2, "Bob", bobOdds, 1.5, market.Won),
+			newTip(2, "Bob", bobOdds, 1.5, market.Lost),
+			newTip(2, "Bob", bobOdds, 1.5, market.Lost),
+		}
+	}
+
+	for _, sortBy := range []string{"profit", "roi"} {
+		t.Run(sortBy, func(t *testing.T) {
+			honest := rankTipsters(tips(1.5), 4, sortBy)
+			inflated := rankTipsters(tips(999.99), 4, sortBy)
+
+			if !reflect.DeepEqual(inflated, honest) {
+				t.Errorf("inflated odds changed the leaderboard:\ngot  %+v\nwant %+v", inflated, honest)
+			}
+
+			if len(inflated) != 2 || inflated[0].UserName != "Alice" {
+				t.Errorf("got %+v; want Alice ranked first", inflated)
+			}
+		})
+	}
+}
+
+func TestScoredOdds(t *testing.T) {
+	reference := func(odds float64) *float64 {
+		return &odds
+	}
+
+	tests := []struct {
+		name string
+		tip  database.Tip
+		want float64
+	}{
+		{"shorter than the model", database.Tip{Odds: 1.8, ReferenceOdds: reference(2)}, 1.8},
+		{"longer than the model", database.Tip{Odds: 3, ReferenceOdds: reference(2)}, 2},
+		{"unpriced", database.Tip{Odds: 4}, 4},
+		{"unpriced and inflated", database.Tip{Odds: 999.99}, maxTipOdds},
+		{"priced above the cap", database.Tip{Odds: 40, ReferenceOdds: reference(25)}, maxTipOdds},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := scoredOdds(tt.tip)
+
+			if got != tt.want {
+				t.Errorf("got %v; want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,473 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/afoejoe/football-predict/internal/database"
+	"github.com/afoejoe/football-predict/internal/password"
+	"github.com/afoejoe/football-predict/internal/request"
+	"github.com/afoejoe/football-predict/internal/response"
+	"github.com/afoejoe/football-predict/internal/token"
+	"github.com/afoejoe/football-predict/internal/validator"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+var tokenTTLs = map[string]time.Duration{
+	database.TokenScopeVerification:  72 * time.Hour,
+	database.TokenScopePasswordReset: time.Hour,
+}
+
+var tokenEmails = map[string]string{
+	database.TokenScopeVerification:  "verify-email.html",
+	database.TokenScopePasswordReset: "password-reset.html",
+}
+
+func (app *application) signup(w http.ResponseWriter, r *http.Request) {
+	var form struct {
+		Name      string              `form:"Name"`
+		Email     string              `form:"Email"`
+		Password  string              `form:"Password"`
+		Validator validator.Validator `form:"-"`
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		data := app.newTemplateData(r)
+		data["Form"] = form
+
+		err := response.Page(w, http.StatusOK, data, "pages/signup.html")
+		if err != nil {
+			app.serverError(w, r, err)
+		}
+
+	case http.MethodPost:
+		err := request.DecodePostForm(r, &form)
+		if err != nil {
+			app.badRequest(w, r, err)
+			return
+		}
+
+		form.Name = strings.TrimSpace(form.Name)
+		form.Email = strings.TrimSpace(form.Email)
+
+		_, found, err := app.db.GetUserByEmail(form.Email)
+		if err != nil {
+			app.serverError(w, r, err)
+			return
+		}
+
+		form.Validator.CheckField(validator.NotBlank(form.Name), "Name", "Name is required")
+		form.Validator.CheckField(validator.MaxRunes(form.Name, 50), "Name", "Name must not be more than 50 characters long")
+
+		form.Validator.CheckField(validator.NotBlank(form.Email), "Email", "Email is required")
+		form.Validator.CheckField(validator.IsEmail(form.Email), "Email", "Must be a valid email address")
+		form.Validator.CheckField(!found, "Email", "Email is already in use")
+
+		checkPassword(&form.Validator, form.Password)
+
+		if form.Validator.HasErrors() {
+			form.Password = ""
+
+			data := app.newTemplateData(r)
+			data["Form"] = form
+
+			err := response.Page(w, http.StatusUnprocessableEntity, data, "pages/signup.html")
+			if err != nil {
+				app.serverError(w, r, err)
+			}
+			return
+		}
+
+		hashedPassword, err := password.Hash(form.Password)
+		if err != nil {
+			app.serverError(w, r, err)
+			return
+		}
+
+		id, err := app.db.InsertUser(form.Name, form.Email, hashedPassword)
+		if err != nil {
+			app.serverError(w, r, err)
+			return
+		}
+
+		err = app.sendTokenEmail(r, &database.User{ID: id, Name: form.Name, Email: form.Email}, database.TokenScopeVerification)
+		if err != nil {
+			app.serverError(w, r, err)
+			return
+		}
+
+		session, err := app.sessionStore.Get(r, "session")
+		if err != nil {
+			app.serverError(w, r, err)
+			return
+		}
+
+		session.Values["userID"] = id
+
+		err = session.Save(r, w)
+		if err != nil {
+			app.serverError(w, r, err)
+			return
+		}
+
+		http.Redirect(w, r, "/profile", http.StatusSeeOther)
+	}
+}
+
+func (app *application) login(w http.ResponseWriter, r *http.Request) {
+	var form struct {
+		Email     string              `form:"Email"`
+		Password  string              `form:"Password"`
+		Validator validator.Validator `form:"-"`
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		data := app.newTemplateData(r)
+		data["Form"] = form
+
+		err := response.Page(w, http.StatusOK, data, "pages/login.html")
+		if err != nil {
+			app.serverError(w, r, err)
+		}
+
+	case http.MethodPost:
+		err := request.DecodePostForm(r, &form)
+		if err != nil {
+			app.badRequest(w, r, err)
+			return
+		}
+
+		form.Email = strings.TrimSpace(form.Email)
+
+		user, found, err := app.db.GetUserByEmail(form.Email)
+		if err != nil {
+			app.serverError(w, r, err)
+			return
+		}
+
+		form.Validator.CheckField(validator.NotBlank(form.Email), "Email", "Email is required")
+		form.Validator.CheckField(form.Password != "", "Password", "Password is required")
+
+		if !form.Validator.HasErrors() {
+			hashedPassword := password.DummyHash
+			if found {
+				hashedPassword = user.HashedPassword
+			}
+
+			passwordMatches, err := password.Matches(form.Password, hashedPassword)
+			if err != nil {
+				app.serverError(w, r, err)
+				return
+			}
+
+			form.Validator.Check(found && passwordMatches, "Email address or password is incorrect")
+		}
+
+		if form.Validator.HasErrors() {
+			form.Password = ""
+
+			data := app.newTemplateData(r)
+			data["Form"] = form
+
+			err := response.Page(w, http.StatusUnprocessableEntity, data, "pages/login.html")
+			if err != nil {
+				app.serverError(w, r, err)
+			}
+			return
+		}
+
+		session, err := app.sessionStore.Get(r, "session")
+		if err != nil {
+			app.serverError(w, r, err)
+			return
+		}
+
+		session.Values["userID"] = user.ID
+		session.Values["sessionVersion"] = user.SessionVersion
+
+		redirectPath, ok := session.Values["redirectPathAfterLogin"].(string)
+		if !ok || !isLocalPath(redirectPath) {
+			redirectPath = "/profile"
+		}
+		delete(session.Values, "redirectPathAfterLogin")
+
+		err = session.Save(r, w)
+		if err != nil {
+			app.serverError(w, r, err)
+			return
+		}
+
+		http.Redirect(w, r, redirectPath, http.StatusSeeOther)
+	}
+}
+
+func (app *application) logout(w http.ResponseWriter, r *http.Request) {
+	session, err := app.sessionStore.Get(r, "session")
+	if err != nil {
+		app.serverError(w, r, err)
+		return
+	}
+
+	delete(session.Values, "userID")
+	delete(session.Values, "sessionVersion")
+
+	err = session.Save(r, w)
+	if err != nil {
+		app.serverError(w, r, err)
+		return
+	}
+
+	http.Redirect(w, r, "/", http.StatusSeeOther)
+}
+
+func (app *application) profile(w http.ResponseWriter, r *http.Request) {
+	bets, summary, err := app.userBets(contextGetAuthenticatedUser(r).ID)
+	if err != nil {
+		app.serverError(w, r, err)
+		return
+	}
+
+	data := app.newTemplateData(r)
+	data["VerificationSent"] = r.URL.Query().Get("verification") == "sent"
+	data["Bets"] = bets
+	data["Summary"] = summary
+
+	err = response.Page(w, http.StatusOK, data, "pages/profile.html")
+	if err != nil {
+		app.serverError(w, r, err)
+	}
+}
+
+func (app *application) verifyEmail(w http.ResponseWriter, r *http.Request) {
+	plaintextToken := httprouter.ParamsFromContext(r.Context()).ByName("token")
+
+	user, found, err := app.db.GetUserForToken(token.Hash(plaintextToken), database.TokenScopeVerification)
+	if err != nil {
+		app.serverError(w, r, err)
+		return
+	}
+
+	if found {
+		err = app.db.SetUserVerified(user.ID)
+		if err != nil {
+			app.serverError(w, r, err)
+			return
+		}
+
+		err = app.db.DeleteTokensForUser(user.ID, database.TokenScopeVerification)
+		if err != nil {
+			app.serverError(w, r, err)
+			return
+		}
+	}
+
+	data := app.newTemplateData(r)
+	data["Verified"] = found
+
+	err = response.Page(w, http.StatusOK, data, "pages/verify-email.html")
+	if err != nil {
+		app.serverError(w, r, err)
+	}
+}
+
+func (app *application) resendVerificationEmail(w http.ResponseWriter, r *http.Request) {
+	user := contextGetAuthenticatedUser(r)
+
+	if !user.Verified {
+		err := app.db.DeleteTokensForUser(user.ID, database.TokenScopeVerification)
+		if err != nil {
+			app.serverError(w, r, err)
+			return
+		}
+
+		err = app.sendTokenEmail(r, user, database.TokenScopeVerification)
+		if err != nil {
+			app.serverError(w, r, err)
+			return
+		}
+	}
+
+	http.Redirect(w, r, "/profile?verification=sent", http.StatusSeeOther)
+}
+
+func (app *application) forgottenPassword(w http.ResponseWriter, r *http.Request) {
+	var form struct {
+		Email     string              `form:"Email"`
+		Validator validator.Validator `form:"-"`
+	}
+
+	data := app.newTemplateData(r)
+
+	switch r.Method {
+	case http.MethodGet:
+		data["Form"] = form
+
+		err := response.Page(w, http.StatusOK, data, "pages/forgotten-password.html")
+		if err != nil {
+			app.serverError(w, r, err)
+		}
+
+	case http.MethodPost:
+		err := request.DecodePostForm(r, &form)
+		if err != nil {
+			app.badRequest(w, r, err)
+			return
+		}
+
+		form.Email = strings.TrimSpace(form.Email)
+
+		form.Validator.CheckField(validator.NotBlank(form.Email), "Email", "Email is required")
+		form.Validator.CheckField(validator.IsEmail(form.Email), "Email", "Must be a valid email address")
+
+		if form.Validator.HasErrors() {
+			data["Form"] = form
+
+			err := response.Page(w, http.StatusUnprocessableEntity, data, "pages/forgotten-password.html")
+			if err != nil {
+				app.serverError(w, r, err)
+			}
+			return
+		}
+
+		user, found, err := app.db.GetUserByEmail(form.Email)
+		if err != nil {
+			app.serverError(w, r, err)
+			return
+		}
+
+		// The response is the same whether or not the address has an account,
+		// so that the form cannot be used to find out who is registered.
+		if found {
+			err = app.sendTokenEmail(r, user, database.TokenScopePasswordReset)
+			if err != nil {
+				app.serverError(w, r, err)
+				return
+			}
+		}
+
+		data["Form"] = form
+		data["Sent"] = true
+
+		err = response.Page(w, http.StatusOK, data, "pages/forgotten-password.html")
+		if err != nil {
+			app.serverError(w, r, err)
+		}
+	}
+}
+
+func (app *application) passwordReset(w http.ResponseWriter, r *http.Request) {
+	plaintextToken := httprouter.ParamsFromContext(r.Context()).ByName("token")
+
+	var form struct {
+		Password  string              `form:"Password"`
+		Validator validator.Validator `form:"-"`
+	}
+
+	user, found, err := app.db.GetUserForToken(token.Hash(plaintextToken), database.TokenScopePasswordReset)
+	if err != nil {
+		app.serverError(w, r, err)
+		return
+	}
+
+	data := app.newTemplateData(r)
+	data["Token"] = plaintextToken
+	data["InvalidToken"] = !found
+
+	if !found {
+		err := response.Page(w, http.StatusNotFound, data, "pages/password-reset.html")
+		if err != nil {
+			app.serverError(w, r, err)
+		}
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		data["Form"] = form
+
+		err := response.Page(w, http.StatusOK, data, "pages/password-reset.html")
+		if err != nil {
+			app.serverError(w, r, err)
+		}
+
+	case http.MethodPost:
+		err := request.DecodePostForm(r, &form)
+		if err != nil {
+			app.badRequest(w, r, err)
+			return
+		}
+
+		checkPassword(&form.Validator, form.Password)
+
+		if form.Validator.HasErrors() {
+			form.Password = ""
+			data["Form"] = form
+
+			err := response.Page(w, http.StatusUnprocessableEntity, data, "pages/password-reset.html")
+			if err != nil {
+				app.serverError(w, r, err)
+			}
+			return
+		}
+
+		hashedPassword, err := password.Hash(form.Password)
+		if err != nil {
+			app.serverError(w, r, err)
+			return
+		}
+
+		err = app.db.ResetUserPassword(user.ID, hashedPassword)
+		if err != nil {
+			app.serverError(w, r, err)
+			return
+		}
+
+		data["Form"] = form
+		data["Done"] = true
+
+		err = response.Page(w, http.StatusOK, data, "pages/password-reset.html")
+		if err != nil {
+			app.serverError(w, r, err)
+		}
+	}
+}
+
+// sendTokenEmail issues a new token with the given scope and emails a link
+// containing it to the user in the background.
+func (app *application) sendTokenEmail(r *http.Request, user *database.User, scope string) error {
+	plaintextToken, err := token.New()
+	if err != nil {
+		return err
+	}
+
+	err = app.db.InsertToken(user.ID, token.Hash(plaintextToken), scope, tokenTTLs[scope])
+	if err != nil {
+		return err
+	}
+
+	app.backgroundTask(r, func() error {
+		return app.mailToken(user, plaintextToken, scope)
+	})
+
+	return nil
+}
+
+func (app *application) mailToken(user *database.User, plaintextToken, scope string) error {
+	data := app.newEmailData()
+	data["Name"] = user.Name
+	data["Token"] = plaintextToken
+	data["TTL"] = tokenTTLs[scope]
+
+	return app.mailer.Send(user.Email, data, tokenEmails[scope])
+}
+
+func checkPassword(v *validator.Validator, plaintextPassword string) {
+	v.CheckField(plaintextPassword != "", "Password", "Password is required")
+	v.CheckField(len(plaintextPassword) >= 8, "Password", "Password is too short")
+	v.CheckField(len(plaintextPassword) <= 72, "Password", "Password is too long")
+	v.CheckField(validator.NotIn(strings.ToLower(plaintextPassword), password.CommonPasswords...), "Password", "Password is too common")
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/afoejoe/football-predict/internal/database"
+	"github.com/afoejoe/football-predict/internal/smtp"
+)
+
+func TestMailTokenPasswordReset(t *testing.T) {
+	transport := smtp.NewMemoryTransport()
+
+	app := &application{
+		mailer: smtp.NewMailer(transport, "Football Predict <no-reply@example.com>", nil),
+	}
+	app.config.baseURL = "https://predict.example.com"
+
+	user := &database.User{Name: "Alice", Email: "alice@example.com"}
+
+	err := app.mailToken(user, "ABCDEFGHIJKLMNOPQRSTUVWXYZ", database.TokenScopePasswordReset)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	msg, ok := transport.Last()
+	if !ok {
+		t.Fatal("no email was delivered")
+	}
+
+	if msg.Recipient != user.Email {
+		t.Errorf("got recipient %q; want %q", msg.Recipient, user.Email)
+	}
+
+	if msg.Subject != "Reset your password" {
+		t.Errorf("got subject %q; want %q", msg.Subject, "Reset your password")
+	}
+
+	link := "https://predict.example.com/password-reset/ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+
+	if !strings.Contains(msg.PlainBody, link) {
+		t.Errorf("plain body does not contain %q:\n%s", link, msg.PlainBody)
+	}
+
+	if !strings.Contains(msg.HTMLBody, `href="`+link+`"`) {
+		t.Errorf("HTML body does not link to %q:\n%s", link, msg.HTMLBody)
+	}
+}
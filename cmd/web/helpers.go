@@ -3,18 +3,42 @@ package main
 import (
 	"fmt"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
 
+	"github.com/afoejoe/football-predict/internal/cookies"
+	"github.com/afoejoe/football-predict/internal/odds"
+	"github.com/afoejoe/football-predict/internal/validator"
 	"github.com/afoejoe/football-predict/internal/version"
+
+	"github.com/julienschmidt/httprouter"
 )
 
 func (app *application) newTemplateData(r *http.Request) map[string]any {
 	data := map[string]any{
-		"Version": version.Get(),
+		"AuthenticatedUser": contextGetAuthenticatedUser(r),
+		"OddsFormat":        app.oddsFormat(r),
+		"OddsFormats":       odds.Formats,
+		"RequestURI":        r.URL.RequestURI(),
+		"Version":           version.Get(),
 	}
 
 	return data
 }
 
+// oddsFormat returns the odds format that the visitor chose, falling back to
+// the usual format for their region when they have not chosen one or the
+// cookie has been tampered with.
+func (app *application) oddsFormat(r *http.Request) odds.Format {
+	value, err := cookies.ReadSigned(r, oddsFormatCookie, app.config.cookie.secretKey)
+	if err == nil && validator.In(odds.Format(value), odds.Formats...) {
+		return odds.Format(value)
+	}
+
+	return odds.DefaultFormat(r.Header.Get("Accept-Language"))
+}
+
 func (app *application) newEmailData() map[string]any {
 	data := map[string]any{
 		"BaseURL": app.config.baseURL,
@@ -42,3 +66,46 @@ func (app *application) backgroundTask(r *http.Request, fn func() error) {
 		}
 	}()
 }
+
+func readIDParam(r *http.Request) (int, error) {
+	return strconv.Atoi(httprouter.ParamsFromContext(r.Context()).ByName("id"))
+}
+
+// isLocalPath reports whether path is safe to redirect to because it stays on
+// this site. Browsers treat a backslash as a slash, so /\example.com is
+// rejected along with //example.com.
+func isLocalPath(path string) bool {
+	if !strings.HasPrefix(path, "/") || strings.HasPrefix(path, "//") || strings.HasPrefix(path, "/\\") {
+		return false
+	}
+
+	u, err := url.Parse(path)
+
+	return err == nil && u.Scheme == "" && u.Host == ""
+}
+
+// isSameOrigin reports whether a request that changes state came from a page
+// on this site. Browsers send credentials cached for basic authentication
+// with cross-site form posts, so the admin pages cannot rely on them alone.
+// Sec-Fetch-Site is checked first and Origin is the fallback for browsers
+// that do not send it. A request with neither header did not come from a
+// browser and is allowed.
+func isSameOrigin(r *http.Request) bool {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+
+	if site := r.Header.Get("Sec-Fetch-Site"); site != "" {
+		return site == "same-origin" || site == "none"
+	}
+
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+
+	u, err := url.Parse(origin)
+
+	return err == nil && u.Host == r.Host
+}
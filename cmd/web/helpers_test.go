@@ -0,0 +1,67 @@
+package main
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestIsLocalPath(t *testing.T) {
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"/", true},
+		{"/prediction/arsenal-v-chelsea", true},
+		{"/search?q=arsenal&page=2", true},
+		{"", false},
+		{"prediction/arsenal-v-chelsea", false},
+		{"https://example.com", false},
+		{"//example.com", false},
+		{"/\\example.com", false},
+		{"/\t/example.com", false},
+	}
+
+	for _, tt := range tests {
+		got := isLocalPath(tt.path)
+
+		if got != tt.want {
+			t.Errorf("isLocalPath(%q) = %t; want %t", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestIsSameOrigin(t *testing.T) {
+	tests := []struct {
+		name    string
+		method  string
+		headers map[string]string
+		want    bool
+	}{
+		{"get from another site", "GET", map[string]string{"Sec-Fetch-Site": "cross-site", "Origin": "https://evil.example"}, true},
+		{"post from same origin", "POST", map[string]string{"Sec-Fetch-Site": "same-origin"}, true},
+		{"post typed by user", "POST", map[string]string{"Sec-Fetch-Site": "none"}, true},
+		{"post from another site", "POST", map[string]string{"Sec-Fetch-Site": "cross-site"}, false},
+		{"post from a sibling subdomain", "POST", map[string]string{"Sec-Fetch-Site": "same-site"}, false},
+		{"fetch metadata wins over origin", "POST", map[string]string{"Sec-Fetch-Site": "cross-site", "Origin": "https://example.com"}, false},
+		{"matching origin", "POST", map[string]string{"Origin": "https://example.com"}, true},
+		{"foreign origin", "POST", map[string]string{"Origin": "https://evil.example"}, false},
+		{"origin with other port", "POST", map[string]string{"Origin": "https://example.com:8443"}, false},
+		{"null origin", "POST", map[string]string{"Origin": "null"}, false},
+		{"no browser headers", "POST", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(tt.method, "https://example.com/admin/teams", nil)
+			for key, value := range tt.headers {
+				r.Header.Set(key, value)
+			}
+
+			got := isSameOrigin(r)
+
+			if got != tt.want {
+				t.Errorf("got %t; want %t", got, tt.want)
+			}
+		})
+	}
+}
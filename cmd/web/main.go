@@ -8,9 +8,11 @@ import (
 	"os"
 	"runtime/debug"
 	"sync"
+	"sync/atomic"
 
 	"github.com/afoejoe/football-predict/internal/database"
 	"github.com/afoejoe/football-predict/internal/smtp"
+	"github.com/afoejoe/football-predict/internal/stats"
 	"github.com/afoejoe/football-predict/internal/version"
 
 	"github.com/gorilla/sessions"
@@ -48,6 +50,14 @@ type config struct {
 		secretKey    string
 		oldSecretKey string
 	}
+	mail struct {
+		transport string
+		dir       string
+	}
+	value struct {
+		threshold     float64
+		kellyFraction float64
+	}
 	smtp struct {
 		host     string
 		port     int
@@ -58,12 +68,15 @@ type config struct {
 }
 
 type application struct {
-	config       config
-	db           *database.DB
-	logger       *slog.Logger
-	mailer       *smtp.Mailer
-	sessionStore *sessions.CookieStore
-	wg           sync.WaitGroup
+	config         config
+	db             *database.DB
+	logger         *slog.Logger
+	mailer         *smtp.Mailer
+	sessionStore   *sessions.CookieStore
+	statsCache     atomic.Pointer[stats.Report]
+	goalModelCache atomic.Pointer[goalModelFit]
+	goalModelMu    sync.Mutex
+	wg             sync.WaitGroup
 }
 
 func run(logger *slog.Logger) error {
@@ -79,6 +92,10 @@ func run(logger *slog.Logger) error {
 	flag.StringVar(&cfg.notifications.email, "notifications-email", "", "contact email address for error notifications")
 	flag.StringVar(&cfg.session.secretKey, "session-secret-key", "cifpelo6vpojukbzz7yqikfuid6tkgru", "secret key for session cookie authentication")
 	flag.StringVar(&cfg.session.oldSecretKey, "session-old-secret-key", "", "previous secret key for session cookie authentication")
+	flag.Float64Var(&cfg.value.threshold, "value-threshold", 5, "expected value, as a percentage, above which a prediction is flagged as a value bet")
+	flag.Float64Var(&cfg.value.kellyFraction, "kelly-fraction", 0.25, "fraction of the full Kelly stake to recommend")
+	flag.StringVar(&cfg.mail.transport, "mail-transport", "smtp", "how to deliver email (smtp|file|memory)")
+	flag.StringVar(&cfg.mail.dir, "mail-dir", "tmp/mail", "directory for .eml files when -mail-transport=file")
 	flag.StringVar(&cfg.smtp.host, "smtp-host", "example.smtp.host", "smtp host")
 	flag.IntVar(&cfg.smtp.port, "smtp-port", 25, "smtp port")
 	flag.StringVar(&cfg.smtp.username, "smtp-username", "example_username", "smtp username")
@@ -100,11 +117,13 @@ func run(logger *slog.Logger) error {
 	}
 	defer db.Close()
 
-	mailer, err := smtp.NewMailer(cfg.smtp.host, cfg.smtp.port, cfg.smtp.username, cfg.smtp.password, cfg.smtp.from)
+	transport, err := newMailTransport(cfg)
 	if err != nil {
 		return err
 	}
 
+	mailer := smtp.NewMailer(transport, cfg.smtp.from, db)
+
 	keyPairs := [][]byte{[]byte(cfg.session.secretKey), nil}
 	if cfg.session.oldSecretKey != "" {
 		keyPairs = append(keyPairs, []byte(cfg.session.oldSecretKey), nil)
@@ -127,5 +146,23 @@ func run(logger *slog.Logger) error {
 		sessionStore: sessionStore,
 	}
 
+	_, err = app.openAPISpec()
+	if err != nil {
+		return err
+	}
+
 	return app.serveHTTP()
 }
+
+func newMailTransport(cfg config) (smtp.Transport, error) {
+	switch cfg.mail.transport {
+	case "smtp":
+		return smtp.NewSMTPTransport(cfg.smtp.host, cfg.smtp.port, cfg.smtp.username, cfg.smtp.password)
+	case "file":
+		return smtp.NewFileTransport(cfg.mail.dir)
+	case "memory":
+		return smtp.NewMemoryTransport(), nil
+	}
+
+	return nil, fmt.Errorf("unknown mail transport %q", cfg.mail.transport)
+}
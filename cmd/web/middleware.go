@@ -5,8 +5,12 @@ import (
 	"fmt"
 	"log/slog"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/afoejoe/football-predict/internal/response"
+	"github.com/afoejoe/football-predict/internal/token"
 
 	"github.com/tomasen/realip"
 	"golang.org/x/crypto/bcrypt"
@@ -79,6 +83,176 @@ func (app *application) requireBasicAuthentication(next http.Handler) http.Handl
 			return
 		}
 
+		if !isSameOrigin(r) {
+			app.crossOriginRequest(w, r)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (app *application) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		session, err := app.sessionStore.Get(r, "session")
+		if err != nil {
+			app.serverError(w, r, err)
+			return
+		}
+
+		userID, ok := session.Values["userID"].(int)
+		if ok {
+			user, found, err := app.db.GetUser(userID)
+			if err != nil {
+				app.serverError(w, r, err)
+				return
+			}
+
+			// Sessions from before the user last reset their password are no
+			// longer valid. New users start at version 0, which is also what a
+			// session without a version reads as.
+			sessionVersion, _ := session.Values["sessionVersion"].(int)
+
+			if found && sessionVersion == user.SessionVersion {
+				r = contextSetAuthenticatedUser(r, user)
+			}
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (app *application) requireAuthenticatedUser(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authenticatedUser := contextGetAuthenticatedUser(r)
+
+		if authenticatedUser == nil {
+			session, err := app.sessionStore.Get(r, "session")
+			if err != nil {
+				app.serverError(w, r, err)
+				return
+			}
+
+			// Only pages can be returned to after logging in. Following a
+			// redirect to a POST-only route such as following a prediction
+			// would fail with 405, so forget any earlier path instead.
+			if r.Method == http.MethodGet || r.Method == http.MethodHead {
+				session.Values["redirectPathAfterLogin"] = r.URL.Path
+			} else {
+				delete(session.Values, "redirectPathAfterLogin")
+			}
+
+			err = session.Save(r, w)
+			if err != nil {
+				app.serverError(w, r, err)
+				return
+			}
+
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return
+		}
+
+		w.Header().Add("Cache-Control", "no-store")
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// requireVerifiedUser is like requireAuthenticatedUser, but also requires the
+// user to have confirmed their email address.
+func (app *application) requireVerifiedUser(next http.Handler) http.Handler {
+	return app.requireAuthenticatedUser(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !contextGetAuthenticatedUser(r).Verified {
+			http.Redirect(w, r, "/profile", http.StatusSeeOther)
+			return
+		}
+
 		next.ServeHTTP(w, r)
+	}))
+}
+
+func (app *application) requireAnonymousUser(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authenticatedUser := contextGetAuthenticatedUser(r)
+
+		if authenticatedUser != nil {
+			http.Redirect(w, r, "/", http.StatusSeeOther)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// authenticateAPIKey requires a valid, unrevoked API key with the scope in a
+// bearer Authorization header, and counts the request against the key's daily
+// quota. Requests are only counted once the scope has been checked, so
+// rejected requests do not use up the quota. The quota resets at midnight UTC.
+func (app *application) authenticateAPIKey(scope string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Authorization")
+
+		plaintext, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || plaintext == "" {
+			app.invalidAPIKeyJSON(w, r)
+			return
+		}
+
+		key, found, err := app.db.GetAPIKeyByHash(token.Hash(plaintext))
+		if err != nil {
+			app.serverErrorJSON(w, r, err)
+			return
+		}
+
+		if !found || key.Revoked() {
+			app.invalidAPIKeyJSON(w, r)
+			return
+		}
+
+		if scope != "" && !key.HasScope(scope) {
+			app.notPermittedJSON(w, r)
+			return
+		}
+
+		requests, err := app.db.RecordAPIKeyUsage(key.ID)
+		if err != nil {
+			app.serverErrorJSON(w, r, err)
+			return
+		}
+
+		reset := time.Now().UTC().Truncate(24 * time.Hour).Add(24 * time.Hour)
+
+		w.Header().Set("X-RateLimit-Limit", strconv.Itoa(key.DailyQuota))
+		w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(max(key.DailyQuota-requests, 0)))
+		w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(reset.Unix(), 10))
+
+		if requests > key.DailyQuota {
+			w.Header().Set("Retry-After", strconv.Itoa(int(time.Until(reset).Seconds())+1))
+			app.rateLimitExceededJSON(w, r)
+			return
+		}
+
+		next.ServeHTTP(w, contextSetAPIKey(r, key))
+	})
+}
+
+// requireAPIScope guards an API endpoint. An empty scope marks a public
+// endpoint, which is open to anonymous requests but still checks and counts
+// an API key when one is sent.
+func (app *application) requireAPIScope(scope string, next http.HandlerFunc) http.Handler {
+	authenticated := app.authenticateAPIKey(scope, next)
+
+	if scope != "" {
+		return authenticated
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") == "" {
+			w.Header().Add("Vary", "Authorization")
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		authenticated.ServeHTTP(w, r)
 	})
 }
@@ -0,0 +1,383 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/afoejoe/football-predict/internal/database"
+	"github.com/afoejoe/football-predict/internal/market"
+	"github.com/afoejoe/football-predict/internal/response"
+	"github.com/afoejoe/football-predict/internal/stats"
+	"github.com/afoejoe/football-predict/internal/validator"
+	"github.com/afoejoe/football-predict/internal/version"
+)
+
+type apiParameter struct {
+	Name        string
+	Type        string
+	Description string
+}
+
+// apiOperation documents one entry in apiRoutes. Response bodies are named
+// after the components in apiSchemas, and an empty name means no body. Path
+// parameters are worked out from the route itself.
+type apiOperation struct {
+	Summary     string
+	Query       []apiParameter
+	RequestBody string
+	Responses   map[int]string
+}
+
+var apiOperations = map[string]apiOperation{
+	"GET /api/v1/search": {
+		Summary: "Search predictions",
+		Query: []apiParameter{
+			{"q", "string", "Web search style query, for example arsenal -draw"},
+			{"page", "integer", "Page number, starting at 1"},
+		},
+		Responses: map[int]string{
+			http.StatusOK:                  "SearchResults",
+			http.StatusUnprocessableEntity: "ValidationError",
+		},
+	},
+	"GET /api/v1/stats": {
+		Summary:   "Track record of settled predictions",
+		Responses: map[int]string{http.StatusOK: "Report"},
+	},
+	"GET /api/v1/predictions": {
+		Summary: "List predictions",
+		Query: []apiParameter{
+			{"competition", "string", "Competition slug"},
+			{"market", "string", "Market, for example 1x2 or over_under"},
+			{"outcome", "string", "Outcome, for example pending or won"},
+			{"featured", "boolean", "Only featured or only non-featured predictions"},
+			{"from", "string", "Earliest kick-off date in YYYY-MM-DD format"},
+			{"to", "string", "Latest kick-off date in YYYY-MM-DD format, inclusive"},
+			{"sort", "string", "One of " + strings.Join(database.PredictionSortValues, ", ") + ". Defaults to -scheduled_at"},
+			{"page", "integer", "Page number, starting at 1"},
+			{"page_size", "integer", "Results per page, between 1 and 100"},
+		},
+		Responses: map[int]string{
+			http.StatusOK:                  "PredictionList",
+			http.StatusUnprocessableEntity: "ValidationError",
+		},
+	},
+	"GET /api/v1/predictions/:slug": {
+		Summary: "Get a prediction",
+		Responses: map[int]string{
+			http.StatusOK:       "PredictionEnvelope",
+			http.StatusNotFound: "Error",
+		},
+	},
+	"POST /api/v1/predictions": {
+		Summary:     "Create a prediction",
+		RequestBody: "PredictionInput",
+		Responses: map[int]string{
+			http.StatusCreated:             "PredictionEnvelope",
+			http.StatusBadRequest:          "Error",
+			http.StatusUnprocessableEntity: "ValidationError",
+		},
+	},
+	"PATCH /api/v1/predictions/:slug": {
+		Summary:     "Update a prediction. Only the keys that are sent are changed",
+		RequestBody: "PredictionInput",
+		Responses: map[int]string{
+			http.StatusOK:                  "PredictionEnvelope",
+			http.StatusBadRequest:          "Error",
+			http.StatusNotFound:            "Error",
+			http.StatusUnprocessableEntity: "ValidationError",
+		},
+	},
+	"DELETE /api/v1/predictions/:slug": {
+		Summary: "Delete a prediction",
+		Responses: map[int]string{
+			http.StatusNoContent: "",
+			http.StatusNotFound:  "Error",
+		},
+	},
+}
+
+var apiSchemas = map[string]any{
+	"Prediction":      apiPrediction{},
+	"PredictionInput": predictionInput{},
+	"Metadata":        database.Metadata{},
+	"SearchResult":    database.SearchResult{},
+	"Report":          stats.Report{},
+	"ValidationError": validator.Validator{},
+	"Error":           struct{ Error string }{},
+	"PredictionEnvelope": struct {
+		Prediction apiPrediction
+	}{},
+	"PredictionList": struct {
+		Predictions []apiPrediction
+		Metadata    database.Metadata
+	}{},
+	"SearchResults": struct {
+		Results  []database.SearchResult
+		Metadata database.Metadata
+	}{},
+}
+
+var operationIDReplacer = strings.NewReplacer("{", "by_", "}", "", ".", "_")
+
+// apiEnums lists the allowed values for string types that are restricted to a
+// fixed set.
+var apiEnums = map[reflect.Type][]string{
+	reflect.TypeOf(market.Market("")):  toStrings(market.Markets),
+	reflect.TypeOf(market.Outcome("")): toStrings(market.Outcomes),
+}
+
+// openAPISpec builds an OpenAPI 3.1 document for apiRoutes. It returns an error
+// if a route has not been documented in apiOperations, so that the document
+// cannot silently fall behind the router.
+func (app *application) openAPISpec() (map[string]any, error) {
+	paths := map[string]map[string]any{}
+
+	for _, route := range app.apiRoutes() {
+		key := route.method + " " + route.path
+
+		operation, ok := apiOperations[key]
+		if !ok {
+			return nil, fmt.Errorf("openapi: route %s is not documented", key)
+		}
+
+		var parameters []map[string]any
+
+		segments := strings.Split(route.path, "/")
+		for i, segment := range segments {
+			if name, ok := strings.CutPrefix(segment, ":"); ok {
+				segments[i] = "{" + name + "}"
+				parameters = append(parameters, map[string]any{"name": name, "in": "path", "required": true, "schema": map[string]any{"type": "string"}})
+			}
+		}
+
+		for _, param := range operation.Query {
+			parameters = append(parameters, map[string]any{"name": param.Name, "in": "query", "description": param.Description, "schema": map[string]any{"type": param.Type}})
+		}
+
+		responses := map[string]any{
+			"401": jsonResponse("Missing, invalid or revoked API key", "Error"),
+			"429": jsonResponse("The API key has used its daily quota", "Error"),
+			"500": jsonResponse("Server error", "Error"),
+		}
+
+		description := "Requires an API key with the " + route.scope + " scope."
+		security := []map[string][]string{{"apiKey": {}}}
+
+		if route.scope == "" {
+			description = "Public. An API key is optional, but a key that is sent must be valid and counts towards its quota."
+			security = []map[string][]string{{}, {"apiKey": {}}}
+			responses["401"] = jsonResponse("Invalid or revoked API key", "Error")
+		} else {
+			responses["403"] = jsonResponse("The API key does not have the "+route.scope+" scope", "Error")
+		}
+
+		for status, schema := range operation.Responses {
+			if schema == "" {
+				responses[fmt.Sprint(status)] = map[string]any{"description": http.StatusText(status)}
+				continue
+			}
+
+			responses[fmt.Sprint(status)] = jsonResponse(http.StatusText(status), schema)
+		}
+
+		op := map[string]any{
+			"operationId": operationIDReplacer.Replace(strings.ToLower(route.method) + strings.Join(segments, "_")),
+			"summary":     operation.Summary,
+			"description": description,
+			"security":    security,
+			"responses":   responses,
+		}
+
+		if parameters != nil {
+			op["parameters"] = parameters
+		}
+
+		if operation.RequestBody != "" {
+			op["requestBody"] = map[string]any{
+				"required": true,
+				"content":  map[string]any{"application/json": map[string]any{"schema": schemaRef(operation.RequestBody)}},
+			}
+		}
+
+		path := strings.Join(segments, "/")
+		if paths[path] == nil {
+			paths[path] = map[string]any{}
+		}
+
+		paths[path][strings.ToLower(route.method)] = op
+	}
+
+	schemas := map[string]any{}
+	for name, value := range apiSchemas {
+		schemas[name] = objectSchema(reflect.TypeOf(value))
+	}
+
+	spec := map[string]any{
+		"openapi": "3.1.0",
+		"info": map[string]any{
+			"title":   "Football Predict API",
+			"version": version.Get(),
+		},
+		"servers": []map[string]any{{"url": app.config.baseURL}},
+		"paths":   paths,
+		"components": map[string]any{
+			"schemas": schemas,
+			"securitySchemes": map[string]any{
+				"apiKey": map[string]any{"type": "http", "scheme": "bearer"},
+			},
+		},
+	}
+
+	return spec, nil
+}
+
+func (app *application) openAPIJSON(w http.ResponseWriter, r *http.Request) {
+	spec, err := app.openAPISpec()
+	if err != nil {
+		app.serverErrorJSON(w, r, err)
+		return
+	}
+
+	err = response.JSON(w, http.StatusOK, spec)
+	if err != nil {
+		app.serverErrorJSON(w, r, err)
+	}
+}
+
+func jsonResponse(description, schema string) map[string]any {
+	return map[string]any{
+		"description": description,
+		"content":     map[string]any{"application/json": map[string]any{"schema": schemaRef(schema)}},
+	}
+}
+
+func schemaRef(name string) map[string]any {
+	return map[string]any{"$ref": "#/components/schemas/" + name}
+}
+
+// jsonSchema describes how encoding/json marshals a value of type t. Pointer
+// fields are nullable, embedded structs are flattened into their parent and
+// types listed in apiSchemas are referenced rather than repeated.
+func jsonSchema(t reflect.Type) map[string]any {
+	for name, value := range apiSchemas {
+		if reflect.TypeOf(value) == t {
+			return schemaRef(name)
+		}
+	}
+
+	if t.Kind() == reflect.Pointer {
+		return nullable(jsonSchema(t.Elem()))
+	}
+
+	if t == reflect.TypeOf(time.Time{}) {
+		return map[string]any{"type": "string", "format": "date-time"}
+	}
+
+	if enum, ok := apiEnums[t]; ok {
+		return map[string]any{"type": "string", "enum": enum}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		return map[string]any{"type": []any{"array", "null"}, "items": jsonSchema(t.Elem())}
+	case reflect.Map:
+		return map[string]any{"type": []any{"object", "null"}, "additionalProperties": jsonSchema(t.Elem())}
+	case reflect.Struct:
+		return objectSchema(t)
+	}
+
+	return map[string]any{}
+}
+
+func objectSchema(t reflect.Type) map[string]any {
+	properties := map[string]any{}
+	addProperties(properties, t)
+
+	return map[string]any{"type": "object", "properties": properties}
+}
+
+// nullable allows null as well as the values that schema matches. Plain types
+// just gain "null" in their type list, while references and enums, which null
+// would not match, are wrapped in anyOf.
+func nullable(schema map[string]any) map[string]any {
+	switch typ := schema["type"].(type) {
+	case string:
+		if _, ok := schema["enum"]; !ok {
+			schema["type"] = []any{typ, "null"}
+			return schema
+		}
+	case []any:
+		if slices.Contains(typ, any("null")) {
+			return schema
+		}
+	}
+
+	return map[string]any{"anyOf": []any{schema, map[string]any{"type": "null"}}}
+}
+
+// addProperties adds the fields of struct type t using the names from their
+// json tags, as encoding/json does. Fields tagged "-" are skipped, and nil
+// pointers with omitempty are left out rather than sent as null, so they are
+// not nullable.
+func addProperties(properties map[string]any, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+
+		name, options, _ := strings.Cut(tag, ",")
+		omitEmpty := slices.Contains(strings.Split(options, ","), "omitempty")
+
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+
+			if embedded.Kind() == reflect.Struct {
+				addProperties(properties, embedded)
+				continue
+			}
+		}
+
+		if !field.IsExported() {
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+
+		if omitEmpty && field.Type.Kind() == reflect.Pointer {
+			properties[name] = jsonSchema(field.Type.Elem())
+			continue
+		}
+
+		properties[name] = jsonSchema(field.Type)
+	}
+}
+
+func toStrings[T ~string](values []T) []string {
+	strs := make([]string, len(values))
+	for i, value := range values {
+		strs[i] = string(value)
+	}
+
+	return strs
+}
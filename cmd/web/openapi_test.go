@@ -0,0 +1,102 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/afoejoe/football-predict/internal/database"
+)
+
+func TestOpenAPISpecDocumentsEveryRoute(t *testing.T) {
+	app := &application{}
+
+	spec, err := app.openAPISpec()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	paths, ok := spec["paths"].(map[string]map[string]any)
+	if !ok {
+		t.Fatalf("paths has type %T", spec["paths"])
+	}
+
+	// Walk the routes registered on the router that is actually served, rather
+	// than apiRoutes, so that a JSON route added anywhere else is caught too.
+	documented := 0
+
+	for _, route := range app.router().routes {
+		if !strings.HasPrefix(route.path, "/api/") || route.path == "/api/openapi.json" {
+			continue
+		}
+
+		segments := strings.Split(route.path, "/")
+		for i, segment := range segments {
+			if name, ok := strings.CutPrefix(segment, ":"); ok {
+				segments[i] = "{" + name + "}"
+			}
+		}
+
+		path := strings.Join(segments, "/")
+
+		if _, ok := paths[path][strings.ToLower(route.method)]; !ok {
+			t.Errorf("%s %s is missing from the OpenAPI document", route.method, path)
+		}
+
+		documented++
+	}
+
+	if documented != len(app.apiRoutes()) {
+		t.Errorf("found %d API routes on the router; want %d", documented, len(app.apiRoutes()))
+	}
+}
+
+func TestJSONSchemaNullable(t *testing.T) {
+	tests := []struct {
+		name string
+		typ  reflect.Type
+		want map[string]any
+	}{
+		{
+			name: "plain type",
+			typ:  reflect.TypeOf((*int)(nil)),
+			want: map[string]any{"type": []any{"integer", "null"}},
+		},
+		{
+			name: "reference",
+			typ:  reflect.TypeOf((*database.Metadata)(nil)),
+			want: map[string]any{"anyOf": []any{schemaRef("Metadata"), map[string]any{"type": "null"}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := jsonSchema(tt.typ)
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v; want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJSONSchemaUsesJSONTags(t *testing.T) {
+	type payload struct {
+		ID       int     `json:"id"`
+		Secret   string  `json:"-"`
+		Note     *string `json:"note,omitempty"`
+		Untagged bool
+	}
+
+	properties := jsonSchema(reflect.TypeOf(payload{}))["properties"].(map[string]any)
+
+	want := map[string]any{
+		"id":       map[string]any{"type": "integer"},
+		"note":     map[string]any{"type": "string"},
+		"Untagged": map[string]any{"type": "boolean"},
+	}
+
+	if !reflect.DeepEqual(properties, want) {
+		t.Errorf("got %v; want %v", properties, want)
+	}
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/afoejoe/football-predict/internal/database"
+	"github.com/afoejoe/football-predict/internal/smtp"
+)
+
+const (
+	outboxPollInterval = 5 * time.Second
+	outboxBatchSize    = 20
+	outboxLease        = 5 * time.Minute
+	outboxMaxAttempts  = 12
+	outboxBaseBackoff  = 30 * time.Second
+	outboxMaxBackoff   = 2 * time.Hour
+)
+
+// runOutbox delivers queued emails until ctx is cancelled, then makes one last
+// pass so that emails queued by background tasks during shutdown are sent
+// rather than left for the next start.
+func (app *application) runOutbox(ctx context.Context) {
+	ticker := time.NewTicker(outboxPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			app.deliverDueEmails()
+		case <-ctx.Done():
+			app.deliverDueEmails()
+			return
+		}
+	}
+}
+
+func (app *application) deliverDueEmails() {
+	for {
+		emails, err := app.db.ClaimDueEmails(outboxBatchSize, outboxLease)
+		if err != nil {
+			app.logger.Error("claiming outbox emails", "error", err)
+			return
+		}
+
+		for _, email := range emails {
+			app.deliverEmail(email)
+		}
+
+		if len(emails) < outboxBatchSize {
+			return
+		}
+	}
+}
+
+func (app *application) deliverEmail(email database.OutboxEmail) {
+	msg := &smtp.Message{
+		Recipient: email.Recipient,
+		Headers:   email.Headers,
+		Subject:   email.Subject,
+		PlainBody: email.PlainBody,
+		HTMLBody:  email.HTMLBody,
+	}
+
+	deliveryErr := app.mailer.Deliver(msg)
+	if deliveryErr == nil {
+		err := app.db.MarkEmailSent(email.ID)
+		if err != nil {
+			app.logger.Error("marking outbox email sent", "id", email.ID, "error", err)
+		}
+		return
+	}
+
+	attempts := email.Attempts + 1
+
+	var nextAttemptAt *time.Time
+	if attempts < outboxMaxAttempts {
+		t := time.Now().Add(outboxBackoff(attempts))
+		nextAttemptAt = &t
+	}
+
+	err := app.db.MarkEmailAttemptFailed(email.ID, deliveryErr.Error(), nextAttemptAt)
+	if err != nil {
+		app.logger.Error("recording outbox email failure", "id", email.ID, "error", err)
+		return
+	}
+
+	if nextAttemptAt == nil {
+		app.logger.Error("gave up delivering email", "id", email.ID, "attempts", attempts, "error", deliveryErr)
+		return
+	}
+
+	app.logger.Warn("email delivery failed", "id", email.ID, "attempts", attempts, "retry_at", *nextAttemptAt, "error", deliveryErr)
+}
+
+// outboxBackoff doubles the wait after each failed attempt, starting at
+// outboxBaseBackoff and capped at outboxMaxBackoff.
+func outboxBackoff(attempts int) time.Duration {
+	backoff := outboxBaseBackoff << (attempts - 1)
+	if backoff <= 0 || backoff > outboxMaxBackoff {
+		return outboxMaxBackoff
+	}
+
+	return backoff
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"time"
+
+	"github.com/afoejoe/football-predict/internal/elo"
+)
+
+// recomputeRatings replays every result from scratch and replaces the stored
+// rating history. It is deterministic, so it can simply be run again after a
+// score is corrected or a fixture deleted.
+func (app *application) recomputeRatings() error {
+	results, err := app.db.ListResults(time.Time{}, time.Now())
+	if err != nil {
+		return err
+	}
+
+	eloResults := make([]elo.Result, len(results))
+	for i, result := range results {
+		eloResults[i] = elo.Result{
+			FixtureID: result.ID,
+			HomeTeam:  result.HomeTeamID,
+			AwayTeam:  result.AwayTeamID,
+			HomeGoals: *result.HomeScore,
+			AwayGoals: *result.AwayScore,
+			PlayedAt:  result.KickoffAt,
+		}
+	}
+
+	_, changes := elo.Compute(eloResults, elo.DefaultOptions)
+
+	return app.db.ReplaceTeamRatings(changes)
+}
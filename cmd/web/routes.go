@@ -4,23 +4,140 @@ import (
 	"net/http"
 
 	"github.com/afoejoe/football-predict/assets"
+	"github.com/afoejoe/football-predict/internal/database"
 
 	"github.com/julienschmidt/httprouter"
 )
 
 func (app *application) routes() http.Handler {
-	mux := httprouter.New()
+	mux := app.router()
+
+	return app.logAccess(app.recoverPanic(app.securityHeaders(app.authenticate(mux))))
+}
+
+func (app *application) router() *router {
+	mux := &router{Router: httprouter.New()}
 	mux.NotFound = http.HandlerFunc(app.notFound)
 
 	fileServer := http.FileServer(http.FS(assets.EmbeddedFiles))
 	mux.Handler("GET", "/static/*filepath", fileServer)
 
 	mux.HandlerFunc("GET", "/", app.home)
-	mux.HandlerFunc("GET", "/admin", app.admin)
-
 	mux.HandlerFunc("GET", "/prediction/:slug", app.single)
+	mux.Handler("POST", "/prediction/:slug/follow", app.requireAuthenticatedUser(http.HandlerFunc(app.followPrediction)))
+	mux.Handler("POST", "/prediction/:slug/unfollow", app.requireAuthenticatedUser(http.HandlerFunc(app.unfollowPrediction)))
+	mux.HandlerFunc("GET", "/team/:slug", app.team)
+	mux.HandlerFunc("GET", "/search", app.search)
+	mux.HandlerFunc("GET", "/stats", app.trackRecord)
+	mux.HandlerFunc("GET", "/leaderboard", app.leaderboard)
+	mux.HandlerFunc("POST", "/odds-format", app.setOddsFormat)
+	mux.HandlerFunc("GET", "/newsletter", app.newsletter)
+	mux.HandlerFunc("POST", "/newsletter", app.newsletter)
+	mux.HandlerFunc("GET", "/newsletter/confirm/:signature", app.newsletterConfirm)
+	mux.HandlerFunc("GET", "/newsletter/unsubscribe/:signature", app.newsletterUnsubscribe)
+	mux.HandlerFunc("POST", "/newsletter/unsubscribe/:signature", app.newsletterUnsubscribe)
+
+	mux.Handler("GET", "/signup", app.requireAnonymousUser(http.HandlerFunc(app.signup)))
+	mux.Handler("POST", "/signup", app.requireAnonymousUser(http.HandlerFunc(app.signup)))
+	mux.Handler("GET", "/login", app.requireAnonymousUser(http.HandlerFunc(app.login)))
+	mux.Handler("POST", "/login", app.requireAnonymousUser(http.HandlerFunc(app.login)))
+	mux.Handler("POST", "/logout", app.requireAuthenticatedUser(http.HandlerFunc(app.logout)))
+	mux.Handler("GET", "/profile", app.requireAuthenticatedUser(http.HandlerFunc(app.profile)))
+	mux.Handler("GET", "/profile/bets.json", app.requireAuthenticatedUser(http.HandlerFunc(app.profileBetsJSON)))
+	mux.Handler("GET", "/tips", app.requireAuthenticatedUser(http.HandlerFunc(app.tips)))
+	mux.Handler("GET", "/tips/fixture/:id", app.requireVerifiedUser(http.HandlerFunc(app.tipFixture)))
+	mux.Handler("POST", "/tips/fixture/:id", app.requireVerifiedUser(http.HandlerFunc(app.tipFixture)))
+	mux.Handler("POST", "/tips/delete/:id", app.requireVerifiedUser(http.HandlerFunc(app.deleteTip)))
+	mux.HandlerFunc("GET", "/verify-email/:token", app.verifyEmail)
+	mux.Handler("POST", "/resend-verification-email", app.requireAuthenticatedUser(http.HandlerFunc(app.resendVerificationEmail)))
+	mux.Handler("GET", "/forgotten-password", app.requireAnonymousUser(http.HandlerFunc(app.forgottenPassword)))
+	mux.Handler("POST", "/forgotten-password", app.requireAnonymousUser(http.HandlerFunc(app.forgottenPassword)))
+	mux.HandlerFunc("GET", "/password-reset/:token", app.passwordReset)
+	mux.HandlerFunc("POST", "/password-reset/:token", app.passwordReset)
+
+	mux.HandlerFunc("GET", "/api/openapi.json", app.openAPIJSON)
+	for _, route := range app.apiRoutes() {
+		mux.Handler(route.method, route.path, app.requireAPIScope(route.scope, route.handler))
+	}
+
+	mux.Handler("GET", "/admin", app.requireBasicAuthentication(http.HandlerFunc(app.admin)))
+	mux.Handler("GET", "/admin/predictions/create", app.requireBasicAuthentication(http.HandlerFunc(app.adminCreatePrediction)))
+	mux.Handler("POST", "/admin/predictions/create", app.requireBasicAuthentication(http.HandlerFunc(app.adminCreatePrediction)))
+	mux.Handler("GET", "/admin/predictions/edit/:id", app.requireBasicAuthentication(http.HandlerFunc(app.adminEditPrediction)))
+	mux.Handler("POST", "/admin/predictions/edit/:id", app.requireBasicAuthentication(http.HandlerFunc(app.adminEditPrediction)))
+	mux.Handler("POST", "/admin/predictions/delete/:id", app.requireBasicAuthentication(http.HandlerFunc(app.adminDeletePrediction)))
+
+	mux.Handler("GET", "/admin/teams", app.requireBasicAuthentication(http.HandlerFunc(app.adminTeams)))
+	mux.Handler("POST", "/admin/teams", app.requireBasicAuthentication(http.HandlerFunc(app.adminTeams)))
+	mux.Handler("GET", "/admin/teams/edit/:id", app.requireBasicAuthentication(http.HandlerFunc(app.adminEditTeam)))
+	mux.Handler("POST", "/admin/teams/edit/:id", app.requireBasicAuthentication(http.HandlerFunc(app.adminEditTeam)))
+	mux.Handler("GET", "/admin/competitions", app.requireBasicAuthentication(http.HandlerFunc(app.adminCompetitions)))
+	mux.Handler("POST", "/admin/competitions", app.requireBasicAuthentication(http.HandlerFunc(app.adminCompetitions)))
+	mux.Handler("GET", "/admin/competitions/edit/:id", app.requireBasicAuthentication(http.HandlerFunc(app.adminEditCompetition)))
+	mux.Handler("POST", "/admin/competitions/edit/:id", app.requireBasicAuthentication(http.HandlerFunc(app.adminEditCompetition)))
+	mux.Handler("GET", "/admin/seasons", app.requireBasicAuthentication(http.HandlerFunc(app.adminSeasons)))
+	mux.Handler("POST", "/admin/seasons", app.requireBasicAuthentication(http.HandlerFunc(app.adminSeasons)))
+	mux.Handler("GET", "/admin/seasons/edit/:id", app.requireBasicAuthentication(http.HandlerFunc(app.adminEditSeason)))
+	mux.Handler("POST", "/admin/seasons/edit/:id", app.requireBasicAuthentication(http.HandlerFunc(app.adminEditSeason)))
+	mux.Handler("GET", "/admin/fixtures", app.requireBasicAuthentication(http.HandlerFunc(app.adminFixtures)))
+	mux.Handler("POST", "/admin/fixtures", app.requireBasicAuthentication(http.HandlerFunc(app.adminFixtures)))
+	mux.Handler("GET", "/admin/fixtures/edit/:id", app.requireBasicAuthentication(http.HandlerFunc(app.adminEditFixture)))
+	mux.Handler("POST", "/admin/fixtures/edit/:id", app.requireBasicAuthentication(http.HandlerFunc(app.adminEditFixture)))
+	mux.Handler("POST", "/admin/fixtures/delete/:id", app.requireBasicAuthentication(http.HandlerFunc(app.adminDeleteFixture)))
+	mux.Handler("POST", "/admin/fixtures/settle/:id", app.requireBasicAuthentication(http.HandlerFunc(app.adminSettleFixture)))
+	mux.Handler("GET", "/admin/api-keys", app.requireBasicAuthentication(http.HandlerFunc(app.adminAPIKeys)))
+	mux.Handler("POST", "/admin/api-keys", app.requireBasicAuthentication(http.HandlerFunc(app.adminAPIKeys)))
+	mux.Handler("POST", "/admin/api-keys/revoke/:id", app.requireBasicAuthentication(http.HandlerFunc(app.adminRevokeAPIKey)))
+	mux.Handler("GET", "/admin/newsletter", app.requireBasicAuthentication(http.HandlerFunc(app.adminNewsletter)))
+	mux.Handler("POST", "/admin/newsletter/digest", app.requireBasicAuthentication(http.HandlerFunc(app.adminSendDigest)))
+	mux.Handler("GET", "/admin/fair-odds", app.requireBasicAuthentication(http.HandlerFunc(app.adminFairOdds)))
+	mux.Handler("GET", "/admin/analytics", app.requireBasicAuthentication(http.HandlerFunc(app.adminAnalytics)))
+	mux.Handler("GET", "/admin/jobs", app.requireBasicAuthentication(http.HandlerFunc(app.adminJobs)))
+	mux.Handler("POST", "/admin/jobs/run/:name", app.requireBasicAuthentication(http.HandlerFunc(app.adminRunJob)))
+	mux.Handler("POST", "/admin/settle", app.requireBasicAuthentication(http.HandlerFunc(app.adminSettlePredictions)))
+
+	return mux
+}
 
-	// mux.Handler("GET", "/basic-auth-protected", app.requireBasicAuthentication(http.HandlerFunc(app.protected)))
+// router records each route as it is registered, so that tests can check the
+// routes that are actually served against the OpenAPI document.
+type router struct {
+	*httprouter.Router
+	routes []registeredRoute
+}
+
+type registeredRoute struct {
+	method string
+	path   string
+}
+
+func (r *router) Handler(method, path string, handler http.Handler) {
+	r.routes = append(r.routes, registeredRoute{method, path})
+	r.Router.Handler(method, path, handler)
+}
+
+func (r *router) HandlerFunc(method, path string, handler http.HandlerFunc) {
+	r.Handler(method, path, handler)
+}
+
+type apiRoute struct {
+	method  string
+	path    string
+	scope   string
+	handler http.HandlerFunc
+}
 
-	return app.logAccess(app.recoverPanic(app.securityHeaders(mux)))
+// apiRoutes lists every versioned JSON API endpoint. The OpenAPI document is
+// generated from this list, and every entry must have a matching operation in
+// apiOperations. Routes with an empty scope are public.
+func (app *application) apiRoutes() []apiRoute {
+	return []apiRoute{
+		{"GET", "/api/v1/search", "", app.searchJSON},
+		{"GET", "/api/v1/stats", "", app.statsJSON},
+		{"GET", "/api/v1/predictions", database.ScopeRead, app.listPredictionsJSON},
+		{"GET", "/api/v1/predictions/:slug", database.ScopeRead, app.getPredictionJSON},
+		{"POST", "/api/v1/predictions", database.ScopeWrite, app.createPredictionJSON},
+		{"PATCH", "/api/v1/predictions/:slug", database.ScopeWrite, app.updatePredictionJSON},
+		{"DELETE", "/api/v1/predictions/:slug", database.ScopeAdmin, app.deletePredictionJSON},
+	}
 }
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/afoejoe/football-predict/internal/cron"
+)
+
+const (
+	jobRunRetention = 30 * 24 * time.Hour
+	emailRetention  = 7 * 24 * time.Hour
+)
+
+type job struct {
+	name     string
+	schedule cron.Schedule
+	run      func() error
+}
+
+// jobs lists the recurring jobs. Schedules are in UTC.
+func (app *application) jobs() []job {
+	return []job{
+		{"settle-predictions", cron.MustParse("*/10 * * * *"), app.settlePredictions},
+		{"send-digest", cron.MustParse("0 18 * * *"), app.sendDigest},
+		{"prune-expired", cron.MustParse("30 3 * * *"), app.pruneExpired},
+		{"refresh-stats", cron.MustParse("*/5 * * * *"), app.refreshStats},
+		{"fit-goal-model", cron.MustParse("15 * * * *"), app.refreshGoalModel},
+		{"recompute-ratings", cron.MustParse("45 4 * * *"), app.recomputeRatings},
+	}
+}
+
+func (app *application) findJob(name string) (job, bool) {
+	for _, j := range app.jobs() {
+		if j.name == name {
+			return j, true
+		}
+	}
+
+	return job{}, false
+}
+
+// runScheduler runs every job on its schedule until ctx is cancelled. It
+// returns once any jobs that are still running have finished.
+func (app *application) runScheduler(ctx context.Context) {
+	var wg sync.WaitGroup
+
+	for _, j := range app.jobs() {
+		wg.Add(1)
+
+		go func(j job) {
+			defer wg.Done()
+
+			for {
+				next := j.schedule.Next(time.Now().UTC())
+				if next.IsZero() {
+					return
+				}
+
+				timer := time.NewTimer(time.Until(next))
+
+				select {
+				case <-ctx.Done():
+					timer.Stop()
+					return
+				case <-timer.C:
+					app.runJob(j, next)
+				}
+			}
+		}(j)
+	}
+
+	wg.Wait()
+}
+
+// runJob runs a job for the time it was scheduled for. The advisory lock stops
+// two instances running it at once, and the unique job_run row for each
+// scheduled time stops a slower instance running it again afterwards.
+func (app *application) runJob(j job, scheduledFor time.Time) {
+	start := time.Now()
+
+	var ran bool
+
+	_, err := app.db.WithAdvisoryLock("job:"+j.name, func() error {
+		id, inserted, err := app.db.InsertJobRun(j.name, scheduledFor)
+		if err != nil || !inserted {
+			return err
+		}
+
+		ran = true
+
+		jobErr := runRecovered(j.run)
+
+		err = app.db.FinishJobRun(id, jobErr)
+		if err != nil {
+			return err
+		}
+
+		return jobErr
+	})
+
+	switch {
+	case err != nil:
+		app.logger.Error("job failed", "job", j.name, "error", err)
+	case ran:
+		app.logger.Info("job finished", "job", j.name, "duration", time.Since(start).String())
+	}
+}
+
+func runRecovered(fn func() error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v\n%s", r, debug.Stack())
+		}
+	}()
+
+	return fn()
+}
+
+func (app *application) pruneExpired() error {
+	tokens, err := app.db.DeleteExpiredTokens()
+	if err != nil {
+		return err
+	}
+
+	runs, err := app.db.DeleteJobRunsBefore(time.Now().Add(-jobRunRetention))
+	if err != nil {
+		return err
+	}
+
+	emails, err := app.db.DeleteFinishedEmailsBefore(time.Now().Add(-emailRetention))
+	if err != nil {
+		return err
+	}
+
+	app.logger.Info("pruned expired rows", "tokens", tokens, "job_runs", runs, "emails", emails)
+
+	return nil
+}
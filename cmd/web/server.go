@@ -42,6 +42,26 @@ func (app *application) serveHTTP() error {
 		shutdownErrorChan <- srv.Shutdown(ctx)
 	}()
 
+	outboxCtx, stopOutbox := context.WithCancel(context.Background())
+	defer stopOutbox()
+
+	outboxDone := make(chan struct{})
+
+	go func() {
+		defer close(outboxDone)
+		app.runOutbox(outboxCtx)
+	}()
+
+	schedulerCtx, stopScheduler := context.WithCancel(context.Background())
+	defer stopScheduler()
+
+	schedulerDone := make(chan struct{})
+
+	go func() {
+		defer close(schedulerDone)
+		app.runScheduler(schedulerCtx)
+	}()
+
 	app.logger.Info("starting server", slog.Group("server", "addr", srv.Addr))
 
 	err := srv.ListenAndServe()
@@ -56,6 +76,13 @@ func (app *application) serveHTTP() error {
 
 	app.logger.Info("stopped server", slog.Group("server", "addr", srv.Addr))
 
+	stopScheduler()
+	<-schedulerDone
+
 	app.wg.Wait()
+
+	stopOutbox()
+	<-outboxDone
+
 	return nil
 }
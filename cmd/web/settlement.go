@@ -0,0 +1,129 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/afoejoe/football-predict/internal/database"
+	"github.com/afoejoe/football-predict/internal/market"
+)
+
+// settlePredictions settles every pending prediction and community tip whose
+// fixture has finished or been cancelled. A prediction or tip that cannot be
+// settled is logged and skipped so that it does not hold up the rest, and the
+// errors are returned together at the end.
+func (app *application) settlePredictions() error {
+	predictions, err := app.db.ListUnsettledPredictions()
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+
+	settled := 0
+
+	for _, prediction := range predictions {
+		outcome, err := settlementOutcome(prediction.FixtureStatus, prediction.HomeScore, prediction.AwayScore, prediction.Market, prediction.Selection, prediction.Line)
+		if err == nil && outcome != market.Pending {
+			err = app.db.SetPredictionOutcome(prediction.ID, outcome)
+			if err == nil {
+				settled++
+			}
+		}
+
+		if err != nil {
+			app.logger.Error("settling prediction", "id", prediction.ID, "error", err)
+			errs = append(errs, fmt.Errorf("prediction %d: %w", prediction.ID, err))
+		}
+	}
+
+	tips, err := app.db.ListUnsettledTips()
+	if err != nil {
+		return errors.Join(append(errs, err)...)
+	}
+
+	settledTips := 0
+
+	for _, tip := range tips {
+		outcome, err := settlementOutcome(tip.FixtureStatus, tip.HomeScore, tip.AwayScore, tip.Market, tip.Selection, tip.Line)
+		if err == nil && outcome != market.Pending {
+			err = app.db.SetTipOutcome(tip.ID, outcome)
+			if err == nil {
+				settledTips++
+			}
+		}
+
+		if err != nil {
+			app.logger.Error("settling tip", "id", tip.ID, "error", err)
+			errs = append(errs, fmt.Errorf("tip %d: %w", tip.ID, err))
+		}
+	}
+
+	app.logger.Info("settled predictions", "count", settled, "tips", settledTips, "failed", len(errs))
+
+	return errors.Join(errs...)
+}
+
+// settleFixture re-evaluates every prediction and tip on a fixture from its
+// current score, so that correcting a result also corrects the outcomes and
+// the team ratings. Like settlePredictions, it carries on past failures.
+func (app *application) settleFixture(fixtureID int) error {
+	predictions, err := app.db.ListFixturePredictions(fixtureID)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+
+	for _, prediction := range predictions {
+		outcome, err := settlementOutcome(prediction.FixtureStatus, prediction.HomeScore, prediction.AwayScore, prediction.Market, prediction.Selection, prediction.Line)
+		if err == nil && outcome != prediction.Outcome {
+			err = app.db.SetPredictionOutcome(prediction.ID, outcome)
+		}
+
+		if err != nil {
+			app.logger.Error("settling prediction", "id", prediction.ID, "error", err)
+			errs = append(errs, fmt.Errorf("prediction %d: %w", prediction.ID, err))
+		}
+	}
+
+	tips, err := app.db.ListFixtureTips(fixtureID)
+	if err != nil {
+		return errors.Join(append(errs, err)...)
+	}
+
+	for _, tip := range tips {
+		outcome, err := settlementOutcome(tip.FixtureStatus, tip.HomeScore, tip.AwayScore, tip.Market, tip.Selection, tip.Line)
+		if err == nil && outcome != tip.Outcome {
+			err = app.db.SetTipOutcome(tip.ID, outcome)
+		}
+
+		if err != nil {
+			app.logger.Error("settling tip", "id", tip.ID, "error", err)
+			errs = append(errs, fmt.Errorf("tip %d: %w", tip.ID, err))
+		}
+	}
+
+	err = app.recomputeRatings()
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	return errors.Join(errs...)
+}
+
+func settlementOutcome(fixtureStatus string, homeScore, awayScore *int, m market.Market, selection string, line float64) (market.Outcome, error) {
+	switch fixtureStatus {
+	case database.FixtureStatusCancelled:
+		return market.Void, nil
+
+	case database.FixtureStatusFinished:
+		if homeScore == nil || awayScore == nil {
+			return market.Pending, errors.New("fixture is finished but has no score")
+		}
+
+		return market.Settle(m, selection, line, *homeScore, *awayScore)
+	}
+
+	return market.Pending, nil
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/afoejoe/football-predict/internal/database"
+	"github.com/afoejoe/football-predict/internal/market"
+)
+
+func TestSettlementOutcome(t *testing.T) {
+	score := func(goals int) *int {
+		return &goals
+	}
+
+	tests := []struct {
+		name      string
+		status    string
+		homeScore *int
+		awayScore *int
+		want      market.Outcome
+		wantErr   bool
+	}{
+		{"scheduled", database.FixtureStatusScheduled, nil, nil, market.Pending, false},
+		{"live", database.FixtureStatusLive, score(1), score(0), market.Pending, false},
+		{"postponed stays pending", database.FixtureStatusPostponed, nil, nil, market.Pending, false},
+		{"cancelled is void", database.FixtureStatusCancelled, nil, nil, market.Void, false},
+		{"cancelled with a score is still void", database.FixtureStatusCancelled, score(2), score(0), market.Void, false},
+		{"finished and won", database.FixtureStatusFinished, score(2), score(1), market.Won, false},
+		{"finished and lost", database.FixtureStatusFinished, score(0), score(1), market.Lost, false},
+		{"finished without a score", database.FixtureStatusFinished, nil, nil, market.Pending, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := settlementOutcome(tt.status, tt.homeScore, tt.awayScore, market.MatchResult, market.Home, 0)
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got error %v; want error %t", err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Errorf("got %q; want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+// Package calibration measures how honest a set of probability forecasts are,
+// by comparing the stated probabilities with how often the forecast events
+// actually happened.
+package calibration
+
+import "math"
+
+// epsilon keeps the log loss finite when a forecast of 0 or 1 turns out wrong.
+const epsilon = 1e-15
+
+type Forecast struct {
+	Probability float64
+	Hit         bool
+}
+
+// Bucket covers forecasts with probabilities in [Lower, Upper). The last
+// bucket also includes forecasts of exactly 1. A well calibrated set of
+// forecasts has a HitRate close to the MeanProbability in every bucket.
+type Bucket struct {
+	Lower           float64
+	Upper           float64
+	Forecasts       int
+	Hits            int
+	MeanProbability float64
+	HitRate         float64
+}
+
+// Gap returns how far the hit rate was above the mean stated probability. A
+// negative gap means the forecasts in the bucket were overconfident.
+func (b Bucket) Gap() float64 {
+	return b.HitRate - b.MeanProbability
+}
+
+// Report summarises a set of forecasts. Lower Brier scores and log losses are
+// better; always forecasting 50% scores 0.25 and 0.693 respectively.
+type Report struct {
+	Forecasts       int
+	Hits            int
+	MeanProbability float64
+	HitRate         float64
+	BrierScore      float64
+	LogLoss         float64
+	Buckets         []Bucket
+}
+
+// Compute builds a report from forecasts, splitting the reliability table into
+// the given number of equally wide buckets. Probabilities outside [0, 1] are
+// clamped.
+func Compute(forecasts []Forecast, buckets int) Report {
+	report := Report{Buckets: make([]Bucket, buckets)}
+
+	for i := range report.Buckets {
+		report.Buckets[i].Lower = float64(i) / float64(buckets)
+		report.Buckets[i].Upper = float64(i+1) / float64(buckets)
+	}
+
+	if len(forecasts) == 0 {
+		return report
+	}
+
+	var totalProbability, totalBrier, totalLogLoss float64
+
+	for _, forecast := range forecasts {
+		p := math.Min(math.Max(forecast.Probability, 0), 1)
+
+		outcome := 0.0
+		if forecast.Hit {
+			outcome = 1
+		}
+
+		totalProbability += p
+		totalBrier += (p - outcome) * (p - outcome)
+		totalLogLoss -= outcome*math.Log(math.Max(p, epsilon)) + (1-outcome)*math.Log(math.Max(1-p, epsilon))
+
+		bucket := &report.Buckets[min(int(p*float64(buckets)), buckets-1)]
+		bucket.Forecasts++
+		bucket.MeanProbability += p
+
+		if forecast.Hit {
+			report.Hits++
+			bucket.Hits++
+		}
+	}
+
+	n := float64(len(forecasts))
+
+	report.Forecasts = len(forecasts)
+	report.MeanProbability = totalProbability / n
+	report.HitRate = float64(report.Hits) / n
+	report.BrierScore = totalBrier / n
+	report.LogLoss = totalLogLoss / n
+
+	for i := range report.Buckets {
+		bucket := &report.Buckets[i]
+		if bucket.Forecasts > 0 {
+			bucket.MeanProbability /= float64(bucket.Forecasts)
+			bucket.HitRate = float64(bucket.Hits) / float64(bucket.Forecasts)
+		}
+	}
+
+	return report
+}
@@ -0,0 +1,115 @@
+package calibration
+
+import (
+	"math"
+	"testing"
+)
+
+func almostEqual(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestComputeEmpty(t *testing.T) {
+	report := Compute(nil, 4)
+
+	if report.Forecasts != 0 || report.Hits != 0 || report.BrierScore != 0 || report.LogLoss != 0 {
+		t.Errorf("got %+v; want an empty report", report)
+	}
+
+	if len(report.Buckets) != 4 {
+		t.Fatalf("got %d buckets; want 4", len(report.Buckets))
+	}
+
+	for i, bucket := range report.Buckets {
+		if !almostEqual(bucket.Lower, float64(i)*0.25) || !almostEqual(bucket.Upper, float64(i+1)*0.25) {
+			t.Errorf("bucket %d: got [%v, %v); want [%v, %v)", i, bucket.Lower, bucket.Upper, float64(i)*0.25, float64(i+1)*0.25)
+		}
+		if bucket.Forecasts != 0 || bucket.HitRate != 0 || bucket.MeanProbability != 0 {
+			t.Errorf("bucket %d: got %+v; want no forecasts", i, bucket)
+		}
+	}
+}
+
+func TestComputeCoinFlip(t *testing.T) {
+	forecasts := []Forecast{
+		{Probability: 0.5, Hit: true},
+		{Probability: 0.5, Hit: false},
+		{Probability: 0.5, Hit: false},
+		{Probability: 0.5, Hit: true},
+		{Probability: 0.5, Hit: true},
+	}
+
+	report := Compute(forecasts, 10)
+
+	if report.Forecasts != 5 || report.Hits != 3 {
+		t.Errorf("got %d forecasts and %d hits; want 5 and 3", report.Forecasts, report.Hits)
+	}
+	if !almostEqual(report.BrierScore, 0.25) {
+		t.Errorf("got Brier score %v; want 0.25", report.BrierScore)
+	}
+	if !almostEqual(report.LogLoss, math.Ln2) {
+		t.Errorf("got log loss %v; want %v", report.LogLoss, math.Ln2)
+	}
+	if !almostEqual(report.MeanProbability, 0.5) || !almostEqual(report.HitRate, 0.6) {
+		t.Errorf("got mean probability %v and hit rate %v; want 0.5 and 0.6", report.MeanProbability, report.HitRate)
+	}
+
+	bucket := report.Buckets[5]
+	if bucket.Forecasts != 5 || !almostEqual(bucket.Gap(), 0.1) {
+		t.Errorf("got bucket %+v with gap %v; want 5 forecasts and a gap of 0.1", bucket, bucket.Gap())
+	}
+}
+
+func TestComputeBuckets(t *testing.T) {
+	forecasts := []Forecast{
+		{Probability: 0, Hit: false},
+		{Probability: 0.2, Hit: false},
+		{Probability: 0.25, Hit: true},
+		{Probability: 0.7, Hit: false},
+		{Probability: 0.8, Hit: true},
+		{Probability: 1, Hit: true},
+		{Probability: 1.2, Hit: true},
+		{Probability: -0.1, Hit: false},
+	}
+
+	report := Compute(forecasts, 4)
+
+	tests := []struct {
+		forecasts       int
+		hits            int
+		meanProbability float64
+	}{
+		{3, 0, 0.2 / 3},
+		{1, 1, 0.25},
+		{1, 0, 0.7},
+		{3, 3, 2.8 / 3},
+	}
+
+	for i, tt := range tests {
+		bucket := report.Buckets[i]
+
+		if bucket.Forecasts != tt.forecasts || bucket.Hits != tt.hits {
+			t.Errorf("bucket %d: got %d forecasts and %d hits; want %d and %d", i, bucket.Forecasts, bucket.Hits, tt.forecasts, tt.hits)
+		}
+		if !almostEqual(bucket.MeanProbability, tt.meanProbability) {
+			t.Errorf("bucket %d: got mean probability %v; want %v", i, bucket.MeanProbability, tt.meanProbability)
+		}
+	}
+}
+
+func TestComputeConfidentMiss(t *testing.T) {
+	report := Compute([]Forecast{{Probability: 1, Hit: false}}, 10)
+
+	if !almostEqual(report.BrierScore, 1) {
+		t.Errorf("got Brier score %v; want 1", report.BrierScore)
+	}
+	if math.IsInf(report.LogLoss, 0) || math.IsNaN(report.LogLoss) {
+		t.Errorf("got log loss %v; want a finite value", report.LogLoss)
+	}
+	if want := -math.Log(epsilon); !almostEqual(report.LogLoss, want) {
+		t.Errorf("got log loss %v; want %v", report.LogLoss, want)
+	}
+	if report.Buckets[9].Forecasts != 1 {
+		t.Errorf("got %d forecasts in the last bucket; want 1", report.Buckets[9].Forecasts)
+	}
+}
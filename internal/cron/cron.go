@@ -0,0 +1,184 @@
+// Package cron parses standard five field cron expressions (minute, hour, day
+// of month, month and day of week) and works out when they next fire.
+package cron
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Schedule struct {
+	expr       string
+	minute     uint64
+	hour       uint64
+	dayOfMonth uint64
+	month      uint64
+	dayOfWeek  uint64
+	// A restricted day of month and day of week match if either matches, as in
+	// crontab(5). Otherwise both have to match. As in Vixie cron, a field that
+	// starts with *, such as */2, does not count as restricted.
+	anyDay bool
+}
+
+type field struct {
+	name     string
+	min, max int
+}
+
+var fields = []field{
+	{"minute", 0, 59},
+	{"hour", 0, 23},
+	{"day of month", 1, 31},
+	{"month", 1, 12},
+	{"day of week", 0, 6},
+}
+
+var shorthands = map[string]string{
+	"@hourly":  "0 * * * *",
+	"@daily":   "0 0 * * *",
+	"@weekly":  "0 0 * * 0",
+	"@monthly": "0 0 1 * *",
+}
+
+// Parse parses an expression such as "*/10 * * * *" or "30 18 * * 1-5". Each
+// field accepts *, single values, ranges, comma separated lists and /step.
+// The shorthands @hourly, @daily, @weekly and @monthly are also accepted.
+func Parse(expr string) (Schedule, error) {
+	spec := expr
+	if shorthand, ok := shorthands[expr]; ok {
+		spec = shorthand
+	}
+
+	parts := strings.Fields(spec)
+	if len(parts) != len(fields) {
+		return Schedule{}, fmt.Errorf("cron: %q must have %d fields", expr, len(fields))
+	}
+
+	bits := make([]uint64, len(fields))
+	for i, part := range parts {
+		b, err := parseField(part, fields[i])
+		if err != nil {
+			return Schedule{}, fmt.Errorf("cron: %q: %w", expr, err)
+		}
+		bits[i] = b
+	}
+
+	schedule := Schedule{
+		expr:       expr,
+		minute:     bits[0],
+		hour:       bits[1],
+		dayOfMonth: bits[2],
+		month:      bits[3],
+		dayOfWeek:  bits[4],
+		anyDay:     !strings.HasPrefix(parts[2], "*") && !strings.HasPrefix(parts[4], "*"),
+	}
+
+	return schedule, nil
+}
+
+func MustParse(expr string) Schedule {
+	schedule, err := Parse(expr)
+	if err != nil {
+		panic(err)
+	}
+
+	return schedule
+}
+
+func (s Schedule) String() string {
+	return s.expr
+}
+
+// Next returns the first time after t, truncated to the minute, at which the
+// schedule fires. It returns the zero time if the schedule never fires, for
+// example on 30 February.
+func (s Schedule) Next(t time.Time) time.Time {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+
+	// Every schedule that can fire at all does so within about four years,
+	// which covers 29 February.
+	limit := t.AddDate(5, 0, 0)
+
+	for t.Before(limit) {
+		switch {
+		case !has(s.month, int(t.Month())):
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+		case !s.matchesDay(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+		case !has(s.hour, t.Hour()):
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+		case !has(s.minute, t.Minute()):
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+
+	return time.Time{}
+}
+
+func (s Schedule) matchesDay(t time.Time) bool {
+	dom := has(s.dayOfMonth, t.Day())
+	dow := has(s.dayOfWeek, int(t.Weekday()))
+
+	if s.anyDay {
+		return dom || dow
+	}
+
+	return dom && dow
+}
+
+func has(bits uint64, n int) bool {
+	return bits&(1<<uint(n)) != 0
+}
+
+func parseField(part string, f field) (uint64, error) {
+	var bits uint64
+
+	for _, item := range strings.Split(part, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(item, "/")
+
+		step := 1
+		if hasStep {
+			n, err := strconv.Atoi(stepPart)
+			if err != nil || n < 1 {
+				return 0, fmt.Errorf("invalid step %q in %s field", stepPart, f.name)
+			}
+			step = n
+		}
+
+		lo, hi := f.min, f.max
+
+		if rangePart != "*" {
+			loPart, hiPart, isRange := strings.Cut(rangePart, "-")
+
+			n, err := strconv.Atoi(loPart)
+			if err != nil {
+				return 0, fmt.Errorf("invalid value %q in %s field", loPart, f.name)
+			}
+			lo, hi = n, n
+
+			if isRange {
+				n, err := strconv.Atoi(hiPart)
+				if err != nil {
+					return 0, fmt.Errorf("invalid value %q in %s field", hiPart, f.name)
+				}
+				hi = n
+			} else if hasStep {
+				hi = f.max
+			}
+		}
+
+		if lo < f.min || hi > f.max || lo > hi {
+			return 0, fmt.Errorf("%q is out of range for %s field (%d-%d)", item, f.name, f.min, f.max)
+		}
+
+		for n := lo; n <= hi; n += step {
+			bits |= 1 << uint(n)
+		}
+	}
+
+	return bits, nil
+}
@@ -0,0 +1,91 @@
+package cron
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseErrors(t *testing.T) {
+	tests := []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 7",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"a * * * *",
+		"1-b * * * *",
+		"@yearly",
+	}
+
+	for _, expr := range tests {
+		_, err := Parse(expr)
+		if err == nil {
+			t.Errorf("Parse(%q): expected an error", expr)
+		}
+	}
+}
+
+func TestNext(t *testing.T) {
+	date := func(year int, month time.Month, day, hour, minute int) time.Time {
+		return time.Date(year, month, day, hour, minute, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name string
+		expr string
+		from time.Time
+		want time.Time
+	}{
+		// 1 June 2024 is a Saturday.
+		{"every minute", "* * * * *", date(2024, 6, 1, 10, 0), date(2024, 6, 1, 10, 1)},
+		{"seconds are truncated", "* * * * *", date(2024, 6, 1, 10, 0).Add(30 * time.Second), date(2024, 6, 1, 10, 1)},
+		{"step", "*/10 * * * *", date(2024, 6, 1, 10, 0), date(2024, 6, 1, 10, 10)},
+		{"step across the hour", "*/10 * * * *", date(2024, 6, 1, 10, 55), date(2024, 6, 1, 11, 0)},
+		{"step from a value", "5/15 * * * *", date(2024, 6, 1, 10, 21), date(2024, 6, 1, 10, 35)},
+		{"step over a range", "0 8-18/4 * * *", date(2024, 6, 1, 12, 0), date(2024, 6, 1, 16, 0)},
+		{"range", "0 9-17 * * *", date(2024, 6, 1, 17, 30), date(2024, 6, 2, 9, 0)},
+		{"list", "0,20,40 * * * *", date(2024, 6, 1, 10, 25), date(2024, 6, 1, 10, 40)},
+		{"weekdays", "30 18 * * 1-5", date(2024, 6, 1, 12, 0), date(2024, 6, 3, 18, 30)},
+		{"hourly", "@hourly", date(2024, 6, 1, 10, 0), date(2024, 6, 1, 11, 0)},
+		{"daily", "@daily", date(2024, 6, 1, 10, 0), date(2024, 6, 2, 0, 0)},
+		{"weekly", "@weekly", date(2024, 6, 1, 10, 0), date(2024, 6, 2, 0, 0)},
+		{"monthly", "@monthly", date(2024, 6, 1, 10, 0), date(2024, 7, 1, 0, 0)},
+
+		// Restricted day of month and day of week fire on either.
+		{"day of month or day of week", "0 0 15 * 1", date(2024, 6, 1, 0, 0), date(2024, 6, 3, 0, 0)},
+		{"day of month or day of week, day of month first", "0 0 4 * 1", date(2024, 6, 3, 12, 0), date(2024, 6, 4, 0, 0)},
+
+		// A field starting with * is not restricted, so both must match: odd
+		// days of the month that are Mondays, rather than any odd day or any
+		// Monday, and the 10th when it falls on an even day of the week.
+		{"stepped day of month and day of week", "0 0 */2 * 1", date(2024, 6, 3, 12, 0), date(2024, 6, 17, 0, 0)},
+		{"stepped day of week and day of month", "0 0 10 * */2", date(2024, 6, 1, 0, 0), date(2024, 8, 10, 0, 0)},
+
+		// Rollover.
+		{"end of month", "0 0 * * *", date(2024, 4, 30, 12, 0), date(2024, 5, 1, 0, 0)},
+		{"end of year", "0 0 * * *", date(2024, 12, 31, 23, 59), date(2025, 1, 1, 0, 0)},
+		{"month to next year", "0 0 1 3 *", date(2024, 6, 1, 0, 0), date(2025, 3, 1, 0, 0)},
+		{"31st skips short months", "0 0 31 * *", date(2024, 4, 1, 0, 0), date(2024, 5, 31, 0, 0)},
+		{"leap day", "0 0 29 2 *", date(2024, 3, 1, 0, 0), date(2028, 2, 29, 0, 0)},
+		{"never", "0 0 30 2 *", date(2024, 1, 1, 0, 0), time.Time{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := Parse(tt.expr)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got := schedule.Next(tt.from)
+			if !got.Equal(tt.want) {
+				t.Errorf("%q after %s: got %s; want %s", tt.expr, tt.from, got, tt.want)
+			}
+		})
+	}
+}
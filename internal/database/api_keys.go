@@ -0,0 +1,160 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+const (
+	ScopeRead  = "read"
+	ScopeWrite = "write"
+	ScopeAdmin = "admin"
+)
+
+var Scopes = []string{ScopeRead, ScopeWrite, ScopeAdmin}
+
+// APIKey is a partner credential for the JSON API. Only a SHA-256 hash of the
+// key is stored; the prefix is kept in the clear so that keys can be told
+// apart in the admin pages.
+type APIKey struct {
+	ID         int            `db:"id"`
+	Owner      string         `db:"owner"`
+	Prefix     string         `db:"prefix"`
+	Hash       []byte         `db:"hash"`
+	Scopes     pq.StringArray `db:"scopes"`
+	DailyQuota int            `db:"daily_quota"`
+	LastUsedAt *time.Time     `db:"last_used_at"`
+	RevokedAt  *time.Time     `db:"revoked_at"`
+	CreatedAt  time.Time      `db:"created_at"`
+}
+
+// HasScope reports whether the key grants the scope. The admin scope implies
+// write, and write implies read.
+func (k APIKey) HasScope(scope string) bool {
+	for _, granted := range k.Scopes {
+		if slices.Index(Scopes, granted) >= slices.Index(Scopes, scope) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (k APIKey) Revoked() bool {
+	return k.RevokedAt != nil
+}
+
+func (db *DB) InsertAPIKey(key *APIKey) error {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	query := `
+		INSERT INTO api_key (owner, prefix, hash, scopes, daily_quota)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at`
+
+	return db.GetContext(ctx, key, query, key.Owner, key.Prefix, key.Hash, key.Scopes, key.DailyQuota)
+}
+
+func (db *DB) GetAPIKeyByHash(hash []byte) (*APIKey, bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	var key APIKey
+
+	query := `SELECT * FROM api_key WHERE hash = $1`
+
+	err := db.GetContext(ctx, &key, query, hash)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, false, nil
+	}
+
+	return &key, true, err
+}
+
+func (db *DB) ListAPIKeys() ([]APIKey, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	var keys []APIKey
+
+	query := `SELECT * FROM api_key ORDER BY revoked_at IS NOT NULL, created_at DESC`
+
+	err := db.SelectContext(ctx, &keys, query)
+	return keys, err
+}
+
+func (db *DB) RevokeAPIKey(id int) error {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	query := `UPDATE api_key SET revoked_at = now() WHERE id = $1 AND revoked_at IS NULL`
+
+	_, err := db.ExecContext(ctx, query, id)
+	return err
+}
+
+// RecordAPIKeyUsage counts a request against the key's quota for the current
+// UTC day and returns the number of requests made so far that day, including
+// this one.
+func (db *DB) RecordAPIKeyUsage(id int) (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	tx, err := db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var requests int
+
+	query := `
+		INSERT INTO api_key_usage (api_key_id, day, requests)
+		VALUES ($1, (now() AT TIME ZONE 'UTC')::date, 1)
+		ON CONFLICT (api_key_id, day) DO UPDATE SET requests = api_key_usage.requests + 1
+		RETURNING requests`
+
+	err = tx.GetContext(ctx, &requests, query, id)
+	if err != nil {
+		return 0, err
+	}
+
+	_, err = tx.ExecContext(ctx, `UPDATE api_key SET last_used_at = now() WHERE id = $1`, id)
+	if err != nil {
+		return 0, err
+	}
+
+	return requests, tx.Commit()
+}
+
+// APIKeyUsageToday returns the number of requests made by each key today,
+// keyed by API key ID.
+func (db *DB) APIKeyUsageToday() (map[int]int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	var rows []struct {
+		APIKeyID int `db:"api_key_id"`
+		Requests int `db:"requests"`
+	}
+
+	query := `SELECT api_key_id, requests FROM api_key_usage WHERE day = (now() AT TIME ZONE 'UTC')::date`
+
+	err := db.SelectContext(ctx, &rows, query)
+	if err != nil {
+		return nil, err
+	}
+
+	usage := make(map[int]int, len(rows))
+	for _, row := range rows {
+		usage[row.APIKeyID] = row.Requests
+	}
+
+	return usage, nil
+}
@@ -0,0 +1,98 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+// Bet records that a user follows a prediction. A user who only follows a
+// prediction has a zero stake and no odds; a user who also backed it records
+// the stake and the odds they took.
+type Bet struct {
+	UserID       int       `db:"user_id"`
+	PredictionID int       `db:"prediction_id"`
+	Stake        float64   `db:"stake"`
+	Odds         *float64  `db:"odds"`
+	CreatedAt    time.Time `db:"created_at"`
+	UpdatedAt    time.Time `db:"updated_at"`
+}
+
+// UserBet is a followed prediction together with the user's stake.
+type UserBet struct {
+	Prediction
+	Stake      float64   `db:"stake"`
+	Odds       *float64  `db:"odds"`
+	FollowedAt time.Time `db:"followed_at"`
+}
+
+func (b UserBet) Staked() bool {
+	return b.Stake > 0 && b.Odds != nil
+}
+
+// Profit returns the user's profit or loss once the prediction is settled.
+func (b UserBet) Profit() float64 {
+	if !b.Staked() {
+		return 0
+	}
+
+	return b.Stake * b.Outcome.Profit(*b.Odds)
+}
+
+func (db *DB) SaveBet(bet *Bet) error {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	query := `
+		INSERT INTO bet (user_id, prediction_id, stake, odds)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (user_id, prediction_id) DO UPDATE
+		SET stake = EXCLUDED.stake, odds = EXCLUDED.odds, updated_at = now()
+		RETURNING created_at, updated_at`
+
+	return db.GetContext(ctx, bet, query, bet.UserID, bet.PredictionID, bet.Stake, bet.Odds)
+}
+
+func (db *DB) GetBet(userID, predictionID int) (*Bet, bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	var bet Bet
+
+	query := `SELECT * FROM bet WHERE user_id = $1 AND prediction_id = $2`
+
+	err := db.GetContext(ctx, &bet, query, userID, predictionID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, false, nil
+	}
+
+	return &bet, true, err
+}
+
+func (db *DB) DeleteBet(userID, predictionID int) error {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	query := `DELETE FROM bet WHERE user_id = $1 AND prediction_id = $2`
+
+	_, err := db.ExecContext(ctx, query, userID, predictionID)
+	return err
+}
+
+func (db *DB) ListUserBets(userID int) ([]UserBet, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	var bets []UserBet
+
+	query := `
+		SELECT ` + predictionColumns + `, bet.stake, bet.odds, bet.created_at AS followed_at
+		FROM bet
+		INNER JOIN prediction ON prediction.id = bet.prediction_id
+		WHERE bet.user_id = $1
+		ORDER BY prediction.scheduled_at DESC, prediction.id DESC`
+
+	err := db.SelectContext(ctx, &bets, query, userID)
+	return bets, err
+}
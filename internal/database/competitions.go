@@ -0,0 +1,155 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+type Competition struct {
+	ID        int       `db:"id"`
+	Name      string    `db:"name"`
+	Slug      string    `db:"slug"`
+	Country   string    `db:"country"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+type Season struct {
+	ID              int       `db:"id"`
+	CompetitionID   int       `db:"competition_id"`
+	CompetitionName string    `db:"competition_name"`
+	Name            string    `db:"name"`
+	StartsOn        time.Time `db:"starts_on"`
+	EndsOn          time.Time `db:"ends_on"`
+	CreatedAt       time.Time `db:"created_at"`
+	UpdatedAt       time.Time `db:"updated_at"`
+}
+
+const seasonSelect = `
+	SELECT season.*, competition.name AS competition_name
+	FROM season
+	INNER JOIN competition ON competition.id = season.competition_id`
+
+func (db *DB) InsertCompetition(competition *Competition) error {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	query := `
+		INSERT INTO competition (name, slug, country)
+		VALUES ($1, $2, $3)
+		RETURNING id, created_at, updated_at`
+
+	return db.GetContext(ctx, competition, query, competition.Name, competition.Slug, competition.Country)
+}
+
+func (db *DB) GetCompetition(id int) (*Competition, bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	var competition Competition
+
+	query := `SELECT * FROM competition WHERE id = $1`
+
+	err := db.GetContext(ctx, &competition, query, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, false, nil
+	}
+
+	return &competition, true, err
+}
+
+func (db *DB) GetCompetitionBySlug(slug string) (*Competition, bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	var competition Competition
+
+	query := `SELECT * FROM competition WHERE slug = $1`
+
+	err := db.GetContext(ctx, &competition, query, slug)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, false, nil
+	}
+
+	return &competition, true, err
+}
+
+func (db *DB) UpdateCompetition(competition *Competition) error {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	query := `
+		UPDATE competition
+		SET name = $1, slug = $2, country = $3, updated_at = now()
+		WHERE id = $4
+		RETURNING updated_at`
+
+	return db.GetContext(ctx, &competition.UpdatedAt, query, competition.Name, competition.Slug, competition.Country, competition.ID)
+}
+
+func (db *DB) ListCompetitions() ([]Competition, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	var competitions []Competition
+
+	query := `SELECT * FROM competition ORDER BY name, id`
+
+	err := db.SelectContext(ctx, &competitions, query)
+	return competitions, err
+}
+
+func (db *DB) InsertSeason(season *Season) error {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	query := `
+		INSERT INTO season (competition_id, name, starts_on, ends_on)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at, updated_at`
+
+	return db.GetContext(ctx, season, query, season.CompetitionID, season.Name, season.StartsOn, season.EndsOn)
+}
+
+func (db *DB) GetSeason(id int) (*Season, bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	var season Season
+
+	query := seasonSelect + ` WHERE season.id = $1`
+
+	err := db.GetContext(ctx, &season, query, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, false, nil
+	}
+
+	return &season, true, err
+}
+
+func (db *DB) UpdateSeason(season *Season) error {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	query := `
+		UPDATE season
+		SET competition_id = $1, name = $2, starts_on = $3, ends_on = $4, updated_at = now()
+		WHERE id = $5
+		RETURNING updated_at`
+
+	return db.GetContext(ctx, &season.UpdatedAt, query, season.CompetitionID, season.Name, season.StartsOn, season.EndsOn, season.ID)
+}
+
+func (db *DB) ListSeasons() ([]Season, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	var seasons []Season
+
+	query := seasonSelect + ` ORDER BY competition.name, season.starts_on DESC, season.id`
+
+	err := db.SelectContext(ctx, &seasons, query)
+	return seasons, err
+}
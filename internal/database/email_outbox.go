@@ -0,0 +1,167 @@
+package database
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+const (
+	EmailStatusPending = "pending"
+	EmailStatusSent    = "sent"
+	EmailStatusFailed  = "failed"
+)
+
+type EmailHeaders map[string]string
+
+func (h EmailHeaders) Value() (driver.Value, error) {
+	if h == nil {
+		return []byte("{}"), nil
+	}
+
+	return json.Marshal(h)
+}
+
+func (h *EmailHeaders) Scan(src any) error {
+	b, ok := src.([]byte)
+	if !ok {
+		return errors.New("email headers must be scanned from []byte")
+	}
+
+	return json.Unmarshal(b, h)
+}
+
+type OutboxEmail struct {
+	ID            int          `db:"id"`
+	Recipient     string       `db:"recipient"`
+	Headers       EmailHeaders `db:"headers"`
+	Subject       string       `db:"subject"`
+	PlainBody     string       `db:"plain_body"`
+	HTMLBody      string       `db:"html_body"`
+	Status        string       `db:"status"`
+	Attempts      int          `db:"attempts"`
+	LastError     string       `db:"last_error"`
+	NextAttemptAt time.Time    `db:"next_attempt_at"`
+	SentAt        *time.Time   `db:"sent_at"`
+	CreatedAt     time.Time    `db:"created_at"`
+	UpdatedAt     time.Time    `db:"updated_at"`
+}
+
+// EnqueueEmail stores a rendered email for the outbox worker to deliver. It
+// satisfies smtp.Outbox.
+func (db *DB) EnqueueEmail(recipient string, headers map[string]string, subject, plainBody, htmlBody string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	query := `
+		INSERT INTO email_outbox (recipient, headers, subject, plain_body, html_body)
+		VALUES ($1, $2, $3, $4, $5)`
+
+	_, err := db.ExecContext(ctx, query, recipient, EmailHeaders(headers), subject, plainBody, htmlBody)
+	return err
+}
+
+// ClaimDueEmails returns up to limit pending emails that are due and pushes
+// their next attempt back by lease, so that another worker does not pick them
+// up while they are being delivered.
+func (db *DB) ClaimDueEmails(limit int, lease time.Duration) ([]OutboxEmail, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	var emails []OutboxEmail
+
+	query := `
+		UPDATE email_outbox
+		SET next_attempt_at = now() + $2 * interval '1 second', updated_at = now()
+		WHERE id IN (
+			SELECT id FROM email_outbox
+			WHERE status = 'pending' AND next_attempt_at <= now()
+			ORDER BY next_attempt_at, id
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`
+
+	err := db.SelectContext(ctx, &emails, query, limit, lease.Seconds())
+	return emails, err
+}
+
+// MarkEmailSent records a delivery. The bodies are cleared, as they can hold
+// password reset and activation links that must not outlive the email.
+func (db *DB) MarkEmailSent(id int) error {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	query := `
+		UPDATE email_outbox
+		SET status = 'sent', attempts = attempts + 1, last_error = '', plain_body = '', html_body = '',
+			sent_at = now(), updated_at = now()
+		WHERE id = $1`
+
+	_, err := db.ExecContext(ctx, query, id)
+	return err
+}
+
+// MarkEmailAttemptFailed records a failed delivery. The email is retried at
+// nextAttemptAt, or marked as failed for good when nextAttemptAt is nil, in
+// which case its bodies are cleared as they are for a sent email.
+func (db *DB) MarkEmailAttemptFailed(id int, deliveryErr string, nextAttemptAt *time.Time) error {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	query := `
+		UPDATE email_outbox
+		SET attempts = attempts + 1, last_error = $2,
+			status = CASE WHEN $3::timestamptz IS NULL THEN 'failed' ELSE 'pending' END,
+			plain_body = CASE WHEN $3::timestamptz IS NULL THEN '' ELSE plain_body END,
+			html_body = CASE WHEN $3::timestamptz IS NULL THEN '' ELSE html_body END,
+			next_attempt_at = coalesce($3, next_attempt_at),
+			updated_at = now()
+		WHERE id = $1`
+
+	_, err := db.ExecContext(ctx, query, id, deliveryErr, nextAttemptAt)
+	return err
+}
+
+// DeleteFinishedEmailsBefore deletes emails that were sent, or that failed for
+// good, before t.
+func (db *DB) DeleteFinishedEmailsBefore(t time.Time) (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	query := `
+		DELETE FROM email_outbox
+		WHERE status IN ('sent', 'failed') AND coalesce(sent_at, updated_at) < $1`
+
+	result, err := db.ExecContext(ctx, query, t)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+type EmailOutboxCounts struct {
+	Pending int `db:"pending"`
+	Sent    int `db:"sent"`
+	Failed  int `db:"failed"`
+}
+
+func (db *DB) CountOutboxEmails() (EmailOutboxCounts, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	var counts EmailOutboxCounts
+
+	query := `
+		SELECT
+			count(*) FILTER (WHERE status = 'pending') AS pending,
+			count(*) FILTER (WHERE status = 'sent') AS sent,
+			count(*) FILTER (WHERE status = 'failed') AS failed
+		FROM email_outbox`
+
+	err := db.GetContext(ctx, &counts, query)
+	return counts, err
+}
@@ -0,0 +1,177 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+const (
+	FixtureStatusScheduled = "scheduled"
+	FixtureStatusLive      = "live"
+	FixtureStatusFinished  = "finished"
+	FixtureStatusPostponed = "postponed"
+	FixtureStatusCancelled = "cancelled"
+)
+
+var FixtureStatuses = []string{FixtureStatusScheduled, FixtureStatusLive, FixtureStatusFinished, FixtureStatusPostponed, FixtureStatusCancelled}
+
+type Fixture struct {
+	ID              int       `db:"id"`
+	SeasonID        int       `db:"season_id"`
+	SeasonName      string    `db:"season_name"`
+	CompetitionID   int       `db:"competition_id"`
+	CompetitionName string    `db:"competition_name"`
+	HomeTeamID      int       `db:"home_team_id"`
+	HomeTeamName    string    `db:"home_team_name"`
+	HomeTeamSlug    string    `db:"home_team_slug"`
+	AwayTeamID      int       `db:"away_team_id"`
+	AwayTeamName    string    `db:"away_team_name"`
+	AwayTeamSlug    string    `db:"away_team_slug"`
+	Venue           string    `db:"venue"`
+	KickoffAt       time.Time `db:"kickoff_at"`
+	Status          string    `db:"status"`
+	HomeScore       *int      `db:"home_score"`
+	AwayScore       *int      `db:"away_score"`
+	CreatedAt       time.Time `db:"created_at"`
+	UpdatedAt       time.Time `db:"updated_at"`
+}
+
+func (f Fixture) Name() string {
+	return f.HomeTeamName + " vs " + f.AwayTeamName
+}
+
+func (f Fixture) HasResult() bool {
+	return f.HomeScore != nil && f.AwayScore != nil
+}
+
+const fixtureSelect = `
+	SELECT fixture.*,
+		season.name AS season_name,
+		competition.id AS competition_id,
+		competition.name AS competition_name,
+		home_team.name AS home_team_name,
+		home_team.slug AS home_team_slug,
+		away_team.name AS away_team_name,
+		away_team.slug AS away_team_slug
+	FROM fixture
+	INNER JOIN season ON season.id = fixture.season_id
+	INNER JOIN competition ON competition.id = season.competition_id
+	INNER JOIN team home_team ON home_team.id = fixture.home_team_id
+	INNER JOIN team away_team ON away_team.id = fixture.away_team_id`
+
+func (db *DB) InsertFixture(fixture *Fixture) error {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	query := `
+		INSERT INTO fixture (season_id, home_team_id, away_team_id, venue, kickoff_at, status, home_score, away_score)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, created_at, updated_at`
+
+	args := []any{fixture.SeasonID, fixture.HomeTeamID, fixture.AwayTeamID, fixture.Venue, fixture.KickoffAt, fixture.Status, fixture.HomeScore, fixture.AwayScore}
+
+	return db.GetContext(ctx, fixture, query, args...)
+}
+
+func (db *DB) GetFixture(id int) (*Fixture, bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	var fixture Fixture
+
+	query := fixtureSelect + ` WHERE fixture.id = $1`
+
+	err := db.GetContext(ctx, &fixture, query, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, false, nil
+	}
+
+	return &fixture, true, err
+}
+
+func (db *DB) UpdateFixture(fixture *Fixture) error {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	query := `
+		UPDATE fixture
+		SET season_id = $1, home_team_id = $2, away_team_id = $3, venue = $4, kickoff_at = $5, status = $6, home_score = $7, away_score = $8, updated_at = now()
+		WHERE id = $9
+		RETURNING updated_at`
+
+	args := []any{fixture.SeasonID, fixture.HomeTeamID, fixture.AwayTeamID, fixture.Venue, fixture.KickoffAt, fixture.Status, fixture.HomeScore, fixture.AwayScore, fixture.ID}
+
+	return db.GetContext(ctx, &fixture.UpdatedAt, query, args...)
+}
+
+func (db *DB) DeleteFixture(id int) error {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	query := `DELETE FROM fixture WHERE id = $1`
+
+	_, err := db.ExecContext(ctx, query, id)
+	return err
+}
+
+func (db *DB) ListFixtures(from, to time.Time) ([]Fixture, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	var fixtures []Fixture
+
+	query := fixtureSelect + `
+		WHERE fixture.kickoff_at >= $1 AND fixture.kickoff_at < $2
+		ORDER BY fixture.kickoff_at, fixture.id`
+
+	err := db.SelectContext(ctx, &fixtures, query, from, to)
+	return fixtures, err
+}
+
+// ListResults lists finished fixtures with a score that kicked off in the
+// given period, oldest first.
+func (db *DB) ListResults(from, to time.Time) ([]Fixture, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	var fixtures []Fixture
+
+	query := fixtureSelect + `
+		WHERE fixture.status = 'finished' AND fixture.home_score IS NOT NULL AND fixture.away_score IS NOT NULL
+		AND fixture.kickoff_at >= $1 AND fixture.kickoff_at < $2
+		ORDER BY fixture.kickoff_at, fixture.id`
+
+	err := db.SelectContext(ctx, &fixtures, query, from, to)
+	return fixtures, err
+}
+
+func (db *DB) ListTeamFixtures(teamID int, limit int) ([]Fixture, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	var fixtures []Fixture
+
+	query := fixtureSelect + `
+		WHERE fixture.home_team_id = $1 OR fixture.away_team_id = $1
+		ORDER BY fixture.kickoff_at DESC, fixture.id DESC
+		LIMIT $2`
+
+	err := db.SelectContext(ctx, &fixtures, query, teamID, limit)
+	return fixtures, err
+}
+
+func (db *DB) ListCompetitionFixtures(competitionID int, from, to time.Time) ([]Fixture, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	var fixtures []Fixture
+
+	query := fixtureSelect + `
+		WHERE competition.id = $1 AND fixture.kickoff_at >= $2 AND fixture.kickoff_at < $3
+		ORDER BY fixture.kickoff_at, fixture.id`
+
+	err := db.SelectContext(ctx, &fixtures, query, competitionID, from, to)
+	return fixtures, err
+}
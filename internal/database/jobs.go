@@ -0,0 +1,148 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"time"
+)
+
+type JobRun struct {
+	ID           int        `db:"id"`
+	Name         string     `db:"name"`
+	ScheduledFor time.Time  `db:"scheduled_for"`
+	StartedAt    time.Time  `db:"started_at"`
+	FinishedAt   *time.Time `db:"finished_at"`
+	Error        *string    `db:"error"`
+}
+
+func (r JobRun) Duration() time.Duration {
+	if r.FinishedAt == nil {
+		return 0
+	}
+
+	return r.FinishedAt.Sub(r.StartedAt)
+}
+
+// WithAdvisoryLock runs fn while holding a Postgres session level advisory lock
+// derived from name, so that only one application instance runs it at a time.
+// It returns false without calling fn if another session holds the lock.
+//
+// If the lock cannot be released, the connection is discarded rather than
+// returned to the pool, as the lock would otherwise stay held by an idle
+// connection and block every other instance.
+func (db *DB) WithAdvisoryLock(name string, fn func() error) (ran bool, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	// Session level locks belong to a connection, so the lock and unlock have
+	// to use the same one rather than whichever the pool hands out.
+	conn, err := db.Connx(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+
+	h := fnv.New64a()
+	h.Write([]byte(name))
+	key := int64(h.Sum64())
+
+	var locked bool
+
+	err = conn.GetContext(ctx, &locked, `SELECT pg_try_advisory_lock($1)`, key)
+	if err != nil || !locked {
+		return false, err
+	}
+
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+		defer cancel()
+
+		var unlocked bool
+
+		unlockErr := conn.GetContext(ctx, &unlocked, `SELECT pg_advisory_unlock($1)`, key)
+		if unlockErr == nil && !unlocked {
+			unlockErr = fmt.Errorf("advisory lock %q was not held", name)
+		}
+
+		if unlockErr != nil {
+			conn.Raw(func(any) error {
+				return driver.ErrBadConn
+			})
+
+			err = errors.Join(err, fmt.Errorf("releasing advisory lock %q: %w", name, unlockErr))
+		}
+	}()
+
+	return true, fn()
+}
+
+// InsertJobRun records the start of a run. It returns false if the job has
+// already run for scheduledFor, which happens when another instance got there
+// first and has since released the advisory lock.
+func (db *DB) InsertJobRun(name string, scheduledFor time.Time) (int, bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	var id int
+
+	query := `
+		INSERT INTO job_run (name, scheduled_for)
+		VALUES ($1, $2)
+		ON CONFLICT (name, scheduled_for) DO NOTHING
+		RETURNING id`
+
+	err := db.GetContext(ctx, &id, query, name, scheduledFor)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, false, nil
+	}
+
+	return id, err == nil, err
+}
+
+func (db *DB) FinishJobRun(id int, jobErr error) error {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	var message *string
+	if jobErr != nil {
+		s := jobErr.Error()
+		message = &s
+	}
+
+	query := `UPDATE job_run SET finished_at = now(), error = $1 WHERE id = $2`
+
+	_, err := db.ExecContext(ctx, query, message, id)
+	return err
+}
+
+func (db *DB) ListJobRuns(limit int) ([]JobRun, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	var runs []JobRun
+
+	query := `
+		SELECT id, name, scheduled_for, started_at, finished_at, error
+		FROM job_run
+		ORDER BY started_at DESC, id DESC
+		LIMIT $1`
+
+	err := db.SelectContext(ctx, &runs, query, limit)
+	return runs, err
+}
+
+func (db *DB) DeleteJobRunsBefore(t time.Time) (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	result, err := db.ExecContext(ctx, `DELETE FROM job_run WHERE started_at < $1`, t)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
@@ -0,0 +1,46 @@
+package database
+
+import "math"
+
+type Pagination struct {
+	Page     int
+	PageSize int
+}
+
+func (p Pagination) limit() int {
+	return p.PageSize
+}
+
+func (p Pagination) offset() int {
+	return (p.Page - 1) * p.PageSize
+}
+
+type Metadata struct {
+	CurrentPage  int
+	PageSize     int
+	FirstPage    int
+	LastPage     int
+	TotalRecords int
+}
+
+func (m Metadata) HasPrevious() bool {
+	return m.CurrentPage > m.FirstPage
+}
+
+func (m Metadata) HasNext() bool {
+	return m.CurrentPage < m.LastPage
+}
+
+func calculateMetadata(totalRecords int, p Pagination) Metadata {
+	if totalRecords == 0 {
+		return Metadata{}
+	}
+
+	return Metadata{
+		CurrentPage:  p.Page,
+		PageSize:     p.PageSize,
+		FirstPage:    1,
+		LastPage:     int(math.Ceil(float64(totalRecords) / float64(p.PageSize))),
+		TotalRecords: totalRecords,
+	}
+}
@@ -0,0 +1,353 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/afoejoe/football-predict/internal/market"
+)
+
+const predictionColumns = `
+	prediction.id, prediction.fixture_id, prediction.title, prediction.slug, prediction.keywords, prediction.body,
+	prediction.market, prediction.selection, prediction.line, prediction.coefficient, prediction.featured,
+	prediction.model_probability, prediction.outcome, prediction.settled_at, prediction.scheduled_at, prediction.created_at,
+	prediction.updated_at`
+
+// Prediction is a published tip. ModelProbability is the goal model's
+// probability for the selection when the prediction was last saved before
+// kickoff, and is nil if the model could not price it.
+type Prediction struct {
+	ID               int            `db:"id"`
+	FixtureID        *int           `db:"fixture_id"`
+	Title            string         `db:"title"`
+	Slug             string         `db:"slug"`
+	Keywords         string         `db:"keywords"`
+	Body             string         `db:"body"`
+	Market           market.Market  `db:"market"`
+	Selection        string         `db:"selection"`
+	Line             float64        `db:"line"`
+	Coefficient      float64        `db:"coefficient"`
+	Featured         bool           `db:"featured"`
+	ModelProbability *float64       `db:"model_probability"`
+	Outcome          market.Outcome `db:"outcome"`
+	SettledAt        *time.Time     `db:"settled_at"`
+	ScheduledAt      time.Time      `db:"scheduled_at"`
+	CreatedAt        time.Time      `db:"created_at"`
+	UpdatedAt        time.Time      `db:"updated_at"`
+}
+
+func (p Prediction) Label() string {
+	return market.Label(p.Market, p.Selection, p.Line)
+}
+
+func (db *DB) InsertPrediction(prediction *Prediction) error {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	query := `
+		INSERT INTO prediction (fixture_id, title, slug, keywords, body, market, selection, line, coefficient, featured, scheduled_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		RETURNING id, created_at, updated_at`
+
+	args := []any{prediction.FixtureID, prediction.Title, prediction.Slug, prediction.Keywords, prediction.Body, prediction.Market, prediction.Selection, prediction.Line, prediction.Coefficient, prediction.Featured, prediction.ScheduledAt}
+
+	return db.GetContext(ctx, prediction, query, args...)
+}
+
+func (db *DB) GetPrediction(id int) (*Prediction, bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	var prediction Prediction
+
+	query := `SELECT ` + predictionColumns + ` FROM prediction WHERE id = $1`
+
+	err := db.GetContext(ctx, &prediction, query, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, false, nil
+	}
+
+	return &prediction, true, err
+}
+
+func (db *DB) GetPredictionBySlug(slug string) (*Prediction, bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	var prediction Prediction
+
+	query := `SELECT ` + predictionColumns + ` FROM prediction WHERE slug = $1`
+
+	err := db.GetContext(ctx, &prediction, query, slug)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, false, nil
+	}
+
+	return &prediction, true, err
+}
+
+func (db *DB) UpdatePrediction(prediction *Prediction) error {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	query := `
+		UPDATE prediction
+		SET fixture_id = $1, title = $2, slug = $3, keywords = $4, body = $5, market = $6, selection = $7, line = $8, coefficient = $9, featured = $10, scheduled_at = $11, updated_at = now()
+		WHERE id = $12
+		RETURNING updated_at`
+
+	args := []any{prediction.FixtureID, prediction.Title, prediction.Slug, prediction.Keywords, prediction.Body, prediction.Market, prediction.Selection, prediction.Line, prediction.Coefficient, prediction.Featured, prediction.ScheduledAt, prediction.ID}
+
+	return db.GetContext(ctx, &prediction.UpdatedAt, query, args...)
+}
+
+func (db *DB) DeletePrediction(id int) error {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	query := `DELETE FROM prediction WHERE id = $1`
+
+	_, err := db.ExecContext(ctx, query, id)
+	return err
+}
+
+func (db *DB) ListPredictions(from, to time.Time) ([]Prediction, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	var predictions []Prediction
+
+	query := `
+		SELECT ` + predictionColumns + `
+		FROM prediction
+		WHERE scheduled_at >= $1 AND scheduled_at < $2
+		ORDER BY scheduled_at, id`
+
+	err := db.SelectContext(ctx, &predictions, query, from, to)
+	return predictions, err
+}
+
+func (db *DB) ListUpcomingPredictions(limit int) ([]Prediction, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	var predictions []Prediction
+
+	query := `
+		SELECT ` + predictionColumns + `
+		FROM prediction
+		WHERE scheduled_at > now()
+		ORDER BY scheduled_at, id
+		LIMIT $1`
+
+	err := db.SelectContext(ctx, &predictions, query, limit)
+	return predictions, err
+}
+
+func (db *DB) ListFeaturedPredictions(limit int) ([]Prediction, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	var predictions []Prediction
+
+	query := `
+		SELECT ` + predictionColumns + `
+		FROM prediction
+		WHERE featured AND scheduled_at > now()
+		ORDER BY scheduled_at, id
+		LIMIT $1`
+
+	err := db.SelectContext(ctx, &predictions, query, limit)
+	return predictions, err
+}
+
+func (db *DB) ListLatestPredictions(limit int) ([]Prediction, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	var predictions []Prediction
+
+	query := `
+		SELECT ` + predictionColumns + `
+		FROM prediction
+		ORDER BY scheduled_at DESC, id DESC
+		LIMIT $1`
+
+	err := db.SelectContext(ctx, &predictions, query, limit)
+	return predictions, err
+}
+
+type UnsettledPrediction struct {
+	Prediction
+	FixtureStatus string `db:"fixture_status"`
+	HomeScore     *int   `db:"home_score"`
+	AwayScore     *int   `db:"away_score"`
+}
+
+func (db *DB) ListUnsettledPredictions() ([]UnsettledPrediction, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	var predictions []UnsettledPrediction
+
+	query := `
+		SELECT ` + predictionColumns + `, fixture.status AS fixture_status, fixture.home_score, fixture.away_score
+		FROM prediction
+		INNER JOIN fixture ON fixture.id = prediction.fixture_id
+		WHERE prediction.outcome = 'pending' AND fixture.status IN ('finished', 'cancelled')
+		ORDER BY fixture.kickoff_at, prediction.id`
+
+	err := db.SelectContext(ctx, &predictions, query)
+	return predictions, err
+}
+
+func (db *DB) ListFixturePredictions(fixtureID int) ([]UnsettledPrediction, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	var predictions []UnsettledPrediction
+
+	query := `
+		SELECT ` + predictionColumns + `, fixture.status AS fixture_status, fixture.home_score, fixture.away_score
+		FROM prediction
+		INNER JOIN fixture ON fixture.id = prediction.fixture_id
+		WHERE fixture.id = $1
+		ORDER BY prediction.id`
+
+	err := db.SelectContext(ctx, &predictions, query, fixtureID)
+	return predictions, err
+}
+
+func (db *DB) SetPredictionOutcome(id int, outcome market.Outcome) error {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	query := `
+		UPDATE prediction
+		SET outcome = $1, settled_at = CASE WHEN $1 = 'pending' THEN NULL ELSE now() END
+		WHERE id = $2`
+
+	_, err := db.ExecContext(ctx, query, outcome, id)
+	return err
+}
+
+func (db *DB) SetPredictionModelProbability(id int, probability *float64) error {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	query := `UPDATE prediction SET model_probability = $1 WHERE id = $2`
+
+	_, err := db.ExecContext(ctx, query, probability, id)
+	return err
+}
+
+type SettledPrediction struct {
+	Prediction
+	CompetitionName *string `db:"competition_name"`
+}
+
+func (db *DB) ListSettledPredictions() ([]SettledPrediction, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	var predictions []SettledPrediction
+
+	query := `
+		SELECT ` + predictionColumns + `, competition.name AS competition_name
+		FROM prediction
+		LEFT JOIN fixture ON fixture.id = prediction.fixture_id
+		LEFT JOIN season ON season.id = fixture.season_id
+		LEFT JOIN competition ON competition.id = season.competition_id
+		WHERE prediction.outcome <> 'pending'
+		ORDER BY prediction.scheduled_at, prediction.id`
+
+	err := db.SelectContext(ctx, &predictions, query)
+	return predictions, err
+}
+
+// PredictionSortValues lists the sort keys accepted by FilterPredictions. A
+// leading hyphen sorts in descending order.
+var PredictionSortValues = []string{"scheduled_at", "-scheduled_at", "created_at", "-created_at", "coefficient", "-coefficient"}
+
+type PredictionFilter struct {
+	Competition string
+	Market      market.Market
+	Outcome     market.Outcome
+	Featured    *bool
+	From        *time.Time
+	To          *time.Time
+	Sort        string
+}
+
+func (f PredictionFilter) orderBy() string {
+	column := strings.TrimPrefix(f.Sort, "-")
+	if !slices.Contains(PredictionSortValues, f.Sort) {
+		column = "scheduled_at"
+	}
+
+	direction := "ASC"
+	if f.Sort == "" || strings.HasPrefix(f.Sort, "-") {
+		direction = "DESC"
+	}
+
+	return "prediction." + column + " " + direction + ", prediction.id " + direction
+}
+
+// FilterPredictions lists predictions matching the filter, where every zero
+// valued field in the filter matches all predictions.
+func (db *DB) FilterPredictions(filter PredictionFilter, pagination Pagination) ([]Prediction, Metadata, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	query := `
+		SELECT count(*) OVER() AS total_records, ` + predictionColumns + `
+		FROM prediction
+		LEFT JOIN fixture ON fixture.id = prediction.fixture_id
+		LEFT JOIN season ON season.id = fixture.season_id
+		LEFT JOIN competition ON competition.id = season.competition_id
+		WHERE ($1 = '' OR competition.slug = $1)
+		AND ($2 = '' OR prediction.market = $2)
+		AND ($3 = '' OR prediction.outcome = $3)
+		AND ($4::boolean IS NULL OR prediction.featured = $4)
+		AND ($5::timestamptz IS NULL OR prediction.scheduled_at >= $5)
+		AND ($6::timestamptz IS NULL OR prediction.scheduled_at < $6)
+		ORDER BY ` + filter.orderBy() + `
+		LIMIT $7 OFFSET $8`
+
+	rows, err := db.QueryxContext(ctx, query, filter.Competition, filter.Market, filter.Outcome, filter.Featured, filter.From, filter.To, pagination.limit(), pagination.offset())
+	if err != nil {
+		return nil, Metadata{}, err
+	}
+	defer rows.Close()
+
+	var (
+		predictions  []Prediction
+		totalRecords int
+	)
+
+	for rows.Next() {
+		var row struct {
+			TotalRecords int `db:"total_records"`
+			Prediction
+		}
+
+		err := rows.StructScan(&row)
+		if err != nil {
+			return nil, Metadata{}, err
+		}
+
+		totalRecords = row.TotalRecords
+		predictions = append(predictions, row.Prediction)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, Metadata{}, err
+	}
+
+	return predictions, calculateMetadata(totalRecords, pagination), nil
+}
@@ -0,0 +1,73 @@
+package database
+
+import (
+	"context"
+	"html"
+	"strings"
+)
+
+// ts_headline wraps matches in these control characters so that the body can be
+// HTML-escaped before they are swapped for <mark> tags.
+const (
+	headlineStartSel = "\x02"
+	headlineStopSel  = "\x03"
+)
+
+var headlineReplacer = strings.NewReplacer(headlineStartSel, "<mark>", headlineStopSel, "</mark>")
+
+type SearchResult struct {
+	Prediction
+	Rank     float64 `db:"rank"`
+	Headline string  `db:"headline"`
+}
+
+func (db *DB) SearchPredictions(search string, pagination Pagination) ([]SearchResult, Metadata, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	query := `
+		SELECT count(*) OVER() AS total_records, ` + predictionColumns + `,
+			ts_rank(fulltext_search, q) AS rank,
+			ts_headline('english', body, q, $2) AS headline
+		FROM prediction, websearch_to_tsquery('english', $1) q
+		WHERE fulltext_search @@ q
+		ORDER BY rank DESC, scheduled_at DESC, id DESC
+		LIMIT $3 OFFSET $4`
+
+	headlineOptions := `StartSel="` + headlineStartSel + `", StopSel="` + headlineStopSel + `", MaxFragments=2, MaxWords=30, MinWords=10`
+
+	rows, err := db.QueryxContext(ctx, query, search, headlineOptions, pagination.limit(), pagination.offset())
+	if err != nil {
+		return nil, Metadata{}, err
+	}
+	defer rows.Close()
+
+	var (
+		results      []SearchResult
+		totalRecords int
+	)
+
+	for rows.Next() {
+		var row struct {
+			TotalRecords int `db:"total_records"`
+			SearchResult
+		}
+
+		err := rows.StructScan(&row)
+		if err != nil {
+			return nil, Metadata{}, err
+		}
+
+		row.Headline = headlineReplacer.Replace(html.EscapeString(row.Headline))
+
+		totalRecords = row.TotalRecords
+		results = append(results, row.SearchResult)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, Metadata{}, err
+	}
+
+	return results, calculateMetadata(totalRecords, pagination), nil
+}
@@ -0,0 +1,161 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+type Subscriber struct {
+	ID                 int        `db:"id"`
+	Email              string     `db:"email"`
+	ConfirmedAt        *time.Time `db:"confirmed_at"`
+	UnsubscribedAt     *time.Time `db:"unsubscribed_at"`
+	LastDigestOn       *time.Time `db:"last_digest_on"`
+	ConfirmationSentAt *time.Time `db:"confirmation_sent_at"`
+	CreatedAt          time.Time  `db:"created_at"`
+	UpdatedAt          time.Time  `db:"updated_at"`
+}
+
+// Active reports whether the subscriber has confirmed their address and has
+// not since unsubscribed.
+func (s Subscriber) Active() bool {
+	return s.ConfirmedAt != nil && s.UnsubscribedAt == nil
+}
+
+// UpsertSubscriber adds an email address to the list, or returns the existing
+// subscriber for it. Someone who previously unsubscribed is treated as a new,
+// unconfirmed subscriber.
+func (db *DB) UpsertSubscriber(email string) (*Subscriber, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	var subscriber Subscriber
+
+	query := `
+		INSERT INTO subscriber (email)
+		VALUES ($1)
+		ON CONFLICT ((lower(email))) DO UPDATE
+		SET confirmed_at = CASE WHEN subscriber.unsubscribed_at IS NULL THEN subscriber.confirmed_at END,
+			unsubscribed_at = NULL,
+			updated_at = now()
+		RETURNING *`
+
+	err := db.GetContext(ctx, &subscriber, query, email)
+	return &subscriber, err
+}
+
+func (db *DB) GetSubscriber(id int) (*Subscriber, bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	var subscriber Subscriber
+
+	query := `SELECT * FROM subscriber WHERE id = $1`
+
+	err := db.GetContext(ctx, &subscriber, query, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, false, nil
+	}
+
+	return &subscriber, true, err
+}
+
+// ClaimConfirmationEmail records that a confirmation email is being sent to a
+// subscriber. It returns false without recording anything if one was sent
+// less than interval ago, so that the form cannot be used to flood an inbox.
+func (db *DB) ClaimConfirmationEmail(id int, interval time.Duration) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	query := `
+		UPDATE subscriber
+		SET confirmation_sent_at = now(), updated_at = now()
+		WHERE id = $1 AND (confirmation_sent_at IS NULL OR confirmation_sent_at <= now() - $2 * interval '1 second')`
+
+	result, err := db.ExecContext(ctx, query, id, interval.Seconds())
+	if err != nil {
+		return false, err
+	}
+
+	rows, err := result.RowsAffected()
+	return rows > 0, err
+}
+
+func (db *DB) ConfirmSubscriber(id int) error {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	query := `
+		UPDATE subscriber
+		SET confirmed_at = coalesce(confirmed_at, now()), unsubscribed_at = NULL, updated_at = now()
+		WHERE id = $1`
+
+	_, err := db.ExecContext(ctx, query, id)
+	return err
+}
+
+func (db *DB) UnsubscribeSubscriber(id int) error {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	query := `
+		UPDATE subscriber
+		SET unsubscribed_at = coalesce(unsubscribed_at, now()), updated_at = now()
+		WHERE id = $1`
+
+	_, err := db.ExecContext(ctx, query, id)
+	return err
+}
+
+// ListDigestSubscribers returns the active subscribers who have not yet been
+// sent the digest for the given day.
+func (db *DB) ListDigestSubscribers(day time.Time) ([]Subscriber, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	var subscribers []Subscriber
+
+	query := `
+		SELECT * FROM subscriber
+		WHERE confirmed_at IS NOT NULL AND unsubscribed_at IS NULL
+		AND (last_digest_on IS NULL OR last_digest_on < $1::date)
+		ORDER BY id`
+
+	err := db.SelectContext(ctx, &subscribers, query, day.Format("2006-01-02"))
+	return subscribers, err
+}
+
+func (db *DB) SetSubscriberDigestSent(id int, day time.Time) error {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	query := `UPDATE subscriber SET last_digest_on = $1::date WHERE id = $2`
+
+	_, err := db.ExecContext(ctx, query, day.Format("2006-01-02"), id)
+	return err
+}
+
+type SubscriberCounts struct {
+	Active       int `db:"active"`
+	Pending      int `db:"pending"`
+	Unsubscribed int `db:"unsubscribed"`
+}
+
+func (db *DB) CountSubscribers() (SubscriberCounts, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	var counts SubscriberCounts
+
+	query := `
+		SELECT
+			count(*) FILTER (WHERE confirmed_at IS NOT NULL AND unsubscribed_at IS NULL) AS active,
+			count(*) FILTER (WHERE confirmed_at IS NULL AND unsubscribed_at IS NULL) AS pending,
+			count(*) FILTER (WHERE unsubscribed_at IS NOT NULL) AS unsubscribed
+		FROM subscriber`
+
+	err := db.GetContext(ctx, &counts, query)
+	return counts, err
+}
@@ -0,0 +1,104 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/afoejoe/football-predict/internal/elo"
+
+	"github.com/lib/pq"
+)
+
+type TeamRating struct {
+	FixtureID    int       `db:"fixture_id"`
+	TeamID       int       `db:"team_id"`
+	Position     int       `db:"position"`
+	RatingBefore float64   `db:"rating_before"`
+	RatingAfter  float64   `db:"rating_after"`
+	KickoffAt    time.Time `db:"kickoff_at"`
+	Home         bool      `db:"home"`
+	OpponentName string    `db:"opponent_name"`
+	OpponentSlug string    `db:"opponent_slug"`
+	GoalsFor     int       `db:"goals_for"`
+	GoalsAgainst int       `db:"goals_against"`
+}
+
+func (r TeamRating) Change() float64 {
+	return r.RatingAfter - r.RatingBefore
+}
+
+// ReplaceTeamRatings deletes the whole rating history and inserts changes in
+// its place, in the order given.
+func (db *DB) ReplaceTeamRatings(changes []elo.Change) error {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	tx, err := db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Two replacements running at once would otherwise both insert into the
+	// emptied table and fail on the primary key.
+	_, err = tx.ExecContext(ctx, `LOCK TABLE team_rating IN EXCLUSIVE MODE`)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM team_rating`)
+	if err != nil {
+		return err
+	}
+
+	var (
+		fixtureIDs = make([]int64, len(changes))
+		teamIDs    = make([]int64, len(changes))
+		positions  = make([]int64, len(changes))
+		before     = make([]float64, len(changes))
+		after      = make([]float64, len(changes))
+	)
+
+	for i, change := range changes {
+		fixtureIDs[i] = int64(change.FixtureID)
+		teamIDs[i] = int64(change.TeamID)
+		positions[i] = int64(i)
+		before[i] = change.Before
+		after[i] = change.After
+	}
+
+	query := `
+		INSERT INTO team_rating (fixture_id, team_id, position, rating_before, rating_after)
+		SELECT * FROM unnest($1::bigint[], $2::bigint[], $3::integer[], $4::double precision[], $5::double precision[])`
+
+	_, err = tx.ExecContext(ctx, query, pq.Array(fixtureIDs), pq.Array(teamIDs), pq.Array(positions), pq.Array(before), pq.Array(after))
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// ListTeamRatings returns a team's rating history, oldest first.
+func (db *DB) ListTeamRatings(teamID int) ([]TeamRating, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	var ratings []TeamRating
+
+	query := `
+		SELECT team_rating.*, fixture.kickoff_at,
+			fixture.home_team_id = team_rating.team_id AS home,
+			opponent.name AS opponent_name,
+			opponent.slug AS opponent_slug,
+			CASE WHEN fixture.home_team_id = team_rating.team_id THEN fixture.home_score ELSE fixture.away_score END AS goals_for,
+			CASE WHEN fixture.home_team_id = team_rating.team_id THEN fixture.away_score ELSE fixture.home_score END AS goals_against
+		FROM team_rating
+		INNER JOIN fixture ON fixture.id = team_rating.fixture_id
+		INNER JOIN team opponent ON opponent.id = CASE WHEN fixture.home_team_id = team_rating.team_id THEN fixture.away_team_id ELSE fixture.home_team_id END
+		WHERE team_rating.team_id = $1
+		ORDER BY team_rating.position`
+
+	err := db.SelectContext(ctx, &ratings, query, teamID)
+	return ratings, err
+}
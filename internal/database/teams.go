@@ -0,0 +1,87 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+type Team struct {
+	ID        int       `db:"id"`
+	Name      string    `db:"name"`
+	Slug      string    `db:"slug"`
+	ShortName string    `db:"short_name"`
+	Country   string    `db:"country"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+func (db *DB) InsertTeam(team *Team) error {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	query := `
+		INSERT INTO team (name, slug, short_name, country)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at, updated_at`
+
+	return db.GetContext(ctx, team, query, team.Name, team.Slug, team.ShortName, team.Country)
+}
+
+func (db *DB) GetTeam(id int) (*Team, bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	var team Team
+
+	query := `SELECT * FROM team WHERE id = $1`
+
+	err := db.GetContext(ctx, &team, query, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, false, nil
+	}
+
+	return &team, true, err
+}
+
+func (db *DB) GetTeamBySlug(slug string) (*Team, bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	var team Team
+
+	query := `SELECT * FROM team WHERE slug = $1`
+
+	err := db.GetContext(ctx, &team, query, slug)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, false, nil
+	}
+
+	return &team, true, err
+}
+
+func (db *DB) UpdateTeam(team *Team) error {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	query := `
+		UPDATE team
+		SET name = $1, slug = $2, short_name = $3, country = $4, updated_at = now()
+		WHERE id = $5
+		RETURNING updated_at`
+
+	return db.GetContext(ctx, &team.UpdatedAt, query, team.Name, team.Slug, team.ShortName, team.Country, team.ID)
+}
+
+func (db *DB) ListTeams() ([]Team, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	var teams []Team
+
+	query := `SELECT * FROM team ORDER BY name, id`
+
+	err := db.SelectContext(ctx, &teams, query)
+	return teams, err
+}
@@ -0,0 +1,208 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/afoejoe/football-predict/internal/market"
+)
+
+// Tip is a community member's pick on a fixture. Each user can make one tip
+// per fixture, which can be changed until kick-off. Odds are entered by the
+// tipster, while ReferenceOdds is the goal model's fair price when the tip was
+// saved, if it could price it.
+type Tip struct {
+	ID            int            `db:"id"`
+	UserID        int            `db:"user_id"`
+	FixtureID     int            `db:"fixture_id"`
+	Market        market.Market  `db:"market"`
+	Selection     string         `db:"selection"`
+	Line          float64        `db:"line"`
+	Odds          float64        `db:"odds"`
+	ReferenceOdds *float64       `db:"reference_odds"`
+	Outcome       market.Outcome `db:"outcome"`
+	SettledAt     *time.Time     `db:"settled_at"`
+	CreatedAt     time.Time      `db:"created_at"`
+	UpdatedAt     time.Time      `db:"updated_at"`
+}
+
+func (t Tip) Label() string {
+	return market.Label(t.Market, t.Selection, t.Line)
+}
+
+type UserTip struct {
+	Tip
+	HomeTeamName string    `db:"home_team_name"`
+	AwayTeamName string    `db:"away_team_name"`
+	KickoffAt    time.Time `db:"kickoff_at"`
+}
+
+func (t UserTip) FixtureName() string {
+	return t.HomeTeamName + " vs " + t.AwayTeamName
+}
+
+type UnsettledTip struct {
+	Tip
+	FixtureStatus string `db:"fixture_status"`
+	HomeScore     *int   `db:"home_score"`
+	AwayScore     *int   `db:"away_score"`
+}
+
+type SettledTip struct {
+	Tip
+	UserName  string    `db:"user_name"`
+	KickoffAt time.Time `db:"kickoff_at"`
+}
+
+const tipColumns = `
+	tip.id, tip.user_id, tip.fixture_id, tip.market, tip.selection, tip.line, tip.odds,
+	tip.reference_odds, tip.outcome, tip.settled_at, tip.created_at, tip.updated_at`
+
+// SaveTip inserts or replaces the user's tip on a fixture. It returns false
+// without saving anything if the fixture has already kicked off or is no longer
+// scheduled.
+func (db *DB) SaveTip(tip *Tip) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	query := `
+		INSERT INTO tip (user_id, fixture_id, market, selection, line, odds, reference_odds)
+		SELECT $1, fixture.id, $3, $4, $5, $6, $7
+		FROM fixture
+		WHERE fixture.id = $2 AND fixture.status = 'scheduled' AND fixture.kickoff_at > now()
+		ON CONFLICT (user_id, fixture_id) DO UPDATE
+		SET market = EXCLUDED.market, selection = EXCLUDED.selection, line = EXCLUDED.line, odds = EXCLUDED.odds,
+			reference_odds = EXCLUDED.reference_odds, updated_at = now()
+		RETURNING id, outcome, created_at, updated_at`
+
+	err := db.GetContext(ctx, tip, query, tip.UserID, tip.FixtureID, tip.Market, tip.Selection, tip.Line, tip.Odds, tip.ReferenceOdds)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+
+	return err == nil, err
+}
+
+func (db *DB) GetUserFixtureTip(userID, fixtureID int) (*Tip, bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	var tip Tip
+
+	query := `SELECT * FROM tip WHERE user_id = $1 AND fixture_id = $2`
+
+	err := db.GetContext(ctx, &tip, query, userID, fixtureID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, false, nil
+	}
+
+	return &tip, true, err
+}
+
+// DeleteTip withdraws a user's tip, as long as the fixture has not kicked off.
+func (db *DB) DeleteTip(userID, tipID int) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	query := `
+		DELETE FROM tip
+		USING fixture
+		WHERE tip.id = $1 AND tip.user_id = $2 AND fixture.id = tip.fixture_id AND fixture.kickoff_at > now()`
+
+	result, err := db.ExecContext(ctx, query, tipID, userID)
+	if err != nil {
+		return false, err
+	}
+
+	rows, err := result.RowsAffected()
+	return rows > 0, err
+}
+
+func (db *DB) ListUserTips(userID, limit int) ([]UserTip, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	var tips []UserTip
+
+	query := `
+		SELECT ` + tipColumns + `, home_team.name AS home_team_name, away_team.name AS away_team_name, fixture.kickoff_at
+		FROM tip
+		INNER JOIN fixture ON fixture.id = tip.fixture_id
+		INNER JOIN team home_team ON home_team.id = fixture.home_team_id
+		INNER JOIN team away_team ON away_team.id = fixture.away_team_id
+		WHERE tip.user_id = $1
+		ORDER BY fixture.kickoff_at DESC, tip.id DESC
+		LIMIT $2`
+
+	err := db.SelectContext(ctx, &tips, query, userID, limit)
+	return tips, err
+}
+
+func (db *DB) ListUnsettledTips() ([]UnsettledTip, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	var tips []UnsettledTip
+
+	query := `
+		SELECT ` + tipColumns + `, fixture.status AS fixture_status, fixture.home_score, fixture.away_score
+		FROM tip
+		INNER JOIN fixture ON fixture.id = tip.fixture_id
+		WHERE tip.outcome = 'pending' AND fixture.status IN ('finished', 'cancelled')
+		ORDER BY fixture.kickoff_at, tip.id`
+
+	err := db.SelectContext(ctx, &tips, query)
+	return tips, err
+}
+
+func (db *DB) ListFixtureTips(fixtureID int) ([]UnsettledTip, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	var tips []UnsettledTip
+
+	query := `
+		SELECT ` + tipColumns + `, fixture.status AS fixture_status, fixture.home_score, fixture.away_score
+		FROM tip
+		INNER JOIN fixture ON fixture.id = tip.fixture_id
+		WHERE tip.fixture_id = $1
+		ORDER BY tip.id`
+
+	err := db.SelectContext(ctx, &tips, query, fixtureID)
+	return tips, err
+}
+
+func (db *DB) SetTipOutcome(id int, outcome market.Outcome) error {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	query := `
+		UPDATE tip
+		SET outcome = $1, settled_at = CASE WHEN $1 = 'pending' THEN NULL ELSE now() END
+		WHERE id = $2`
+
+	_, err := db.ExecContext(ctx, query, outcome, id)
+	return err
+}
+
+// ListSettledTips returns settled tips on fixtures that kicked off at or after
+// since, or every settled tip when since is nil.
+func (db *DB) ListSettledTips(since *time.Time) ([]SettledTip, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	var tips []SettledTip
+
+	query := `
+		SELECT ` + tipColumns + `, users.name AS user_name, fixture.kickoff_at
+		FROM tip
+		INNER JOIN users ON users.id = tip.user_id
+		INNER JOIN fixture ON fixture.id = tip.fixture_id
+		WHERE tip.outcome <> 'pending' AND ($1::timestamptz IS NULL OR fixture.kickoff_at >= $1)
+		ORDER BY fixture.kickoff_at, tip.id`
+
+	err := db.SelectContext(ctx, &tips, query, since)
+	return tips, err
+}
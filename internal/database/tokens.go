@@ -0,0 +1,69 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+const (
+	TokenScopeVerification  = "verification"
+	TokenScopePasswordReset = "password-reset"
+)
+
+func (db *DB) InsertToken(userID int, hash []byte, scope string, ttl time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	query := `
+		INSERT INTO token (hash, user_id, scope, expires_at)
+		VALUES ($1, $2, $3, $4)`
+
+	_, err := db.ExecContext(ctx, query, hash, userID, scope, time.Now().Add(ttl))
+	return err
+}
+
+// GetUserForToken returns the user that an unexpired token with the given scope
+// was issued to.
+func (db *DB) GetUserForToken(hash []byte, scope string) (*User, bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	var user User
+
+	query := `
+		SELECT users.*
+		FROM users
+		INNER JOIN token ON token.user_id = users.id
+		WHERE token.hash = $1 AND token.scope = $2 AND token.expires_at > now()`
+
+	err := db.GetContext(ctx, &user, query, hash, scope)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, false, nil
+	}
+
+	return &user, true, err
+}
+
+func (db *DB) DeleteTokensForUser(userID int, scope string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	query := `DELETE FROM token WHERE user_id = $1 AND scope = $2`
+
+	_, err := db.ExecContext(ctx, query, userID, scope)
+	return err
+}
+
+func (db *DB) DeleteExpiredTokens() (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	result, err := db.ExecContext(ctx, `DELETE FROM token WHERE expires_at <= now()`)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
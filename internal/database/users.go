@@ -0,0 +1,112 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+// User is a registered member. SessionVersion is stored in the session at
+// login, and sessions with an older version are no longer accepted, so
+// changing it logs the user out everywhere.
+type User struct {
+	ID             int       `db:"id"`
+	Name           string    `db:"name"`
+	Email          string    `db:"email"`
+	HashedPassword string    `db:"hashed_password"`
+	Verified       bool      `db:"verified"`
+	SessionVersion int       `db:"session_version"`
+	CreatedAt      time.Time `db:"created_at"`
+	UpdatedAt      time.Time `db:"updated_at"`
+}
+
+func (db *DB) InsertUser(name, email, hashedPassword string) (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	var id int
+
+	query := `
+		INSERT INTO users (name, email, hashed_password)
+		VALUES ($1, $2, $3)
+		RETURNING id`
+
+	err := db.GetContext(ctx, &id, query, name, email, hashedPassword)
+	return id, err
+}
+
+func (db *DB) GetUser(id int) (*User, bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	var user User
+
+	query := `SELECT * FROM users WHERE id = $1`
+
+	err := db.GetContext(ctx, &user, query, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, false, nil
+	}
+
+	return &user, true, err
+}
+
+func (db *DB) GetUserByEmail(email string) (*User, bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	var user User
+
+	query := `SELECT * FROM users WHERE lower(email) = lower($1)`
+
+	err := db.GetContext(ctx, &user, query, email)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, false, nil
+	}
+
+	return &user, true, err
+}
+
+// ResetUserPassword changes the password of a user who followed a password
+// reset link. Following the link proves that they own the email address, so
+// they are also marked as verified. Their session version is bumped, which
+// logs them out of every existing session, and all of their outstanding
+// tokens are deleted.
+func (db *DB) ResetUserPassword(id int, hashedPassword string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	tx, err := db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+		UPDATE users
+		SET hashed_password = $1, verified = true, session_version = session_version + 1, updated_at = now()
+		WHERE id = $2`
+
+	_, err = tx.ExecContext(ctx, query, hashedPassword, id)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM token WHERE user_id = $1`, id)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (db *DB) SetUserVerified(id int) error {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	query := `UPDATE users SET verified = true, updated_at = now() WHERE id = $1`
+
+	_, err := db.ExecContext(ctx, query, id)
+	return err
+}
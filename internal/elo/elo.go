@@ -0,0 +1,118 @@
+// Package elo rates football teams with the Elo system used by the World
+// Football Elo Ratings: the rating change after a match is scaled by the goal
+// difference, and the home side is given a fixed rating bonus when working out
+// the expected result.
+package elo
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+type Options struct {
+	// Initial is the rating of a team before its first match.
+	Initial float64
+	// K is the rating change for a one goal result between equal teams.
+	K float64
+	// HomeAdvantage is added to the home team's rating when working out the
+	// expected result.
+	HomeAdvantage float64
+}
+
+var DefaultOptions = Options{
+	Initial:       1500,
+	K:             20,
+	HomeAdvantage: 65,
+}
+
+type Result struct {
+	FixtureID int
+	HomeTeam  int
+	AwayTeam  int
+	HomeGoals int
+	AwayGoals int
+	PlayedAt  time.Time
+}
+
+// Change is one team's rating movement from one result.
+type Change struct {
+	FixtureID int
+	TeamID    int
+	PlayedAt  time.Time
+	Before    float64
+	After     float64
+}
+
+// Expected returns the expected score, between 0 and 1, of a team rated a
+// against a team rated b, where a draw scores 0.5.
+func Expected(a, b float64) float64 {
+	return 1 / (1 + math.Pow(10, (b-a)/400))
+}
+
+// Multiplier scales the rating change by the goal difference.
+func Multiplier(goalDifference int) float64 {
+	n := goalDifference
+	if n < 0 {
+		n = -n
+	}
+
+	switch n {
+	case 0, 1:
+		return 1
+	case 2:
+		return 1.5
+	}
+
+	return (11 + float64(n)) / 8
+}
+
+// Compute replays results from scratch and returns every team's final rating
+// along with the change for each team in each result. Results are replayed in
+// order of kick-off time and then fixture ID, whatever order they are passed
+// in, so the same results always give the same ratings.
+func Compute(results []Result, opts Options) (map[int]float64, []Change) {
+	ordered := make([]Result, len(results))
+	copy(ordered, results)
+
+	sort.SliceStable(ordered, func(i, j int) bool {
+		if !ordered[i].PlayedAt.Equal(ordered[j].PlayedAt) {
+			return ordered[i].PlayedAt.Before(ordered[j].PlayedAt)
+		}
+		return ordered[i].FixtureID < ordered[j].FixtureID
+	})
+
+	ratings := map[int]float64{}
+	changes := make([]Change, 0, 2*len(ordered))
+
+	rating := func(team int) float64 {
+		if r, ok := ratings[team]; ok {
+			return r
+		}
+		return opts.Initial
+	}
+
+	for _, result := range ordered {
+		home, away := rating(result.HomeTeam), rating(result.AwayTeam)
+
+		actual := 0.5
+		switch {
+		case result.HomeGoals > result.AwayGoals:
+			actual = 1
+		case result.HomeGoals < result.AwayGoals:
+			actual = 0
+		}
+
+		delta := opts.K * Multiplier(result.HomeGoals-result.AwayGoals) * (actual - Expected(home+opts.HomeAdvantage, away))
+
+		ratings[result.HomeTeam] = home + delta
+		ratings[result.AwayTeam] = away - delta
+
+		changes = append(changes,
+			Change{FixtureID: result.FixtureID, TeamID: result.HomeTeam, PlayedAt: result.PlayedAt, Before: home, After: home + delta},
+			Change{FixtureID: result.FixtureID, TeamID: result.AwayTeam, PlayedAt: result.PlayedAt, Before: away, After: away - delta},
+		)
+	}
+
+	return ratings, changes
+}
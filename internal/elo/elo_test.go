@@ -0,0 +1,154 @@
+package elo
+
+import (
+	"math"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestMultiplier(t *testing.T) {
+	tests := []struct {
+		goalDifference int
+		want           float64
+	}{
+		{0, 1},
+		{1, 1},
+		{-1, 1},
+		{2, 1.5},
+		{-2, 1.5},
+		{3, 1.75},
+		{4, 1.875},
+		{-4, 1.875},
+	}
+
+	for _, tt := range tests {
+		got := Multiplier(tt.goalDifference)
+
+		if got != tt.want {
+			t.Errorf("Multiplier(%d) = %v; want %v", tt.goalDifference, got, tt.want)
+		}
+	}
+}
+
+func TestComputeSingleResult(t *testing.T) {
+	at := time.Date(2024, 8, 17, 15, 0, 0, 0, time.UTC)
+	opts := Options{Initial: 1500, K: 20}
+
+	tests := []struct {
+		name                 string
+		homeGoals, awayGoals int
+		wantHome, wantAway   float64
+	}{
+		{"home win", 1, 0, 1510, 1490},
+		{"away win", 0, 1, 1490, 1510},
+		{"draw", 2, 2, 1500, 1500},
+		{"home win by two", 2, 0, 1515, 1485},
+		{"away win by four", 0, 4, 1481.25, 1518.75},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ratings, changes := Compute([]Result{
+				{FixtureID: 1, HomeTeam: 1, AwayTeam: 2, HomeGoals: tt.homeGoals, AwayGoals: tt.awayGoals, PlayedAt: at},
+			}, opts)
+
+			if ratings[1] != tt.wantHome || ratings[2] != tt.wantAway {
+				t.Errorf("got ratings %v and %v; want %v and %v", ratings[1], ratings[2], tt.wantHome, tt.wantAway)
+			}
+
+			want := []Change{
+				{FixtureID: 1, TeamID: 1, PlayedAt: at, Before: 1500, After: tt.wantHome},
+				{FixtureID: 1, TeamID: 2, PlayedAt: at, Before: 1500, After: tt.wantAway},
+			}
+			if !reflect.DeepEqual(changes, want) {
+				t.Errorf("got changes %+v; want %+v", changes, want)
+			}
+		})
+	}
+}
+
+func TestComputeHomeAdvantage(t *testing.T) {
+	ratings, _ := Compute([]Result{
+		{FixtureID: 1, HomeTeam: 1, AwayTeam: 2, HomeGoals: 1, AwayGoals: 1},
+	}, DefaultOptions)
+
+	want := 1500 + DefaultOptions.K*(0.5-Expected(1565, 1500))
+
+	if math.Abs(ratings[1]-want) > 1e-9 {
+		t.Errorf("got home rating %v; want %v", ratings[1], want)
+	}
+	if ratings[1] >= 1500 {
+		t.Errorf("got home rating %v; a home draw between equal teams should lose rating", ratings[1])
+	}
+}
+
+func results() []Result {
+	at := time.Date(2024, 8, 17, 15, 0, 0, 0, time.UTC)
+
+	return []Result{
+		{FixtureID: 1, HomeTeam: 1, AwayTeam: 2, HomeGoals: 3, AwayGoals: 0, PlayedAt: at},
+		{FixtureID: 2, HomeTeam: 3, AwayTeam: 4, HomeGoals: 1, AwayGoals: 1, PlayedAt: at},
+		{FixtureID: 3, HomeTeam: 2, AwayTeam: 3, HomeGoals: 0, AwayGoals: 2, PlayedAt: at.AddDate(0, 0, 7)},
+		{FixtureID: 4, HomeTeam: 4, AwayTeam: 1, HomeGoals: 2, AwayGoals: 1, PlayedAt: at.AddDate(0, 0, 7)},
+		{FixtureID: 5, HomeTeam: 1, AwayTeam: 3, HomeGoals: 5, AwayGoals: 1, PlayedAt: at.AddDate(0, 0, 14)},
+		{FixtureID: 6, HomeTeam: 2, AwayTeam: 4, HomeGoals: 1, AwayGoals: 0, PlayedAt: at.AddDate(0, 0, 14)},
+	}
+}
+
+func TestComputeIsZeroSum(t *testing.T) {
+	ratings, changes := Compute(results(), DefaultOptions)
+
+	var total float64
+	for _, rating := range ratings {
+		total += rating
+	}
+
+	if want := 4 * DefaultOptions.Initial; math.Abs(total-want) > 1e-9 {
+		t.Errorf("got total rating %v; want %v", total, want)
+	}
+
+	if len(changes) != 12 {
+		t.Fatalf("got %d changes; want 12", len(changes))
+	}
+
+	for i := 0; i < len(changes); i += 2 {
+		home, away := changes[i], changes[i+1]
+
+		if home.FixtureID != away.FixtureID {
+			t.Fatalf("got changes for fixtures %d and %d next to each other", home.FixtureID, away.FixtureID)
+		}
+		if diff := (home.After - home.Before) + (away.After - away.Before); math.Abs(diff) > 1e-9 {
+			t.Errorf("fixture %d: got net rating change %v; want 0", home.FixtureID, diff)
+		}
+	}
+}
+
+func TestComputeOrder(t *testing.T) {
+	wantRatings, wantChanges := Compute(results(), DefaultOptions)
+
+	ordered := results()
+	var shuffled []Result
+	for _, i := range []int{5, 2, 4, 0, 3, 1} {
+		shuffled = append(shuffled, ordered[i])
+	}
+
+	ratings, changes := Compute(shuffled, DefaultOptions)
+
+	if !reflect.DeepEqual(ratings, wantRatings) {
+		t.Errorf("got ratings %v; want %v", ratings, wantRatings)
+	}
+	if !reflect.DeepEqual(changes, wantChanges) {
+		t.Errorf("got changes %+v; want %+v", changes, wantChanges)
+	}
+
+	for i, change := range changes {
+		if want := i/2 + 1; change.FixtureID != want {
+			t.Errorf("change %d: got fixture %d; want %d", i, change.FixtureID, want)
+		}
+	}
+
+	if shuffled[0].FixtureID != 6 {
+		t.Errorf("Compute reordered its input")
+	}
+}
@@ -6,11 +6,14 @@ import (
 	"html/template"
 	"math"
 	"net/url"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
 	"unicode"
 
+	"github.com/afoejoe/football-predict/internal/odds"
+
 	"golang.org/x/text/language"
 	"golang.org/x/text/message"
 )
@@ -38,13 +41,18 @@ var TemplateFuncs = template.FuncMap{
 	"safeHTML":  safeHTML,
 
 	// Slice functions
-	"join": strings.Join,
+	"join":           strings.Join,
+	"containsString": slices.Contains[[]string],
 
 	// Number functions
 	"incr":        incr,
 	"decr":        decr,
 	"formatInt":   formatInt,
 	"formatFloat": formatFloat,
+	"percent":     percent,
+
+	// Betting functions
+	"formatOdds": odds.Display,
 
 	// Boolean functions
 	"yesno": yesno,
@@ -169,6 +177,10 @@ func formatFloat(f float64, dp int) string {
 	return printer.Sprintf(format, f)
 }
 
+func percent(f float64) float64 {
+	return f * 100
+}
+
 func yesno(b bool) string {
 	if b {
 		return "Yes"
@@ -0,0 +1,327 @@
+// Package goalmodel fits a Dixon-Coles style Poisson model of football scores
+// and uses it to price betting markets.
+//
+// Each team has an attack and a defence strength. The expected goals for the
+// home side are attack(home) * defence(away) * home advantage, and for the
+// away side attack(away) * defence(home). Older results are down-weighted
+// exponentially, and the Dixon-Coles rho parameter corrects the probabilities
+// of the low scoring results 0-0, 1-0, 0-1 and 1-1, which independent Poisson
+// distributions get wrong.
+package goalmodel
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	"github.com/afoejoe/football-predict/internal/market"
+)
+
+// MaxGoals is the highest number of goals per team in a score matrix. The
+// probability of more is negligible and is spread over the matrix when it is
+// normalized.
+const MaxGoals = 10
+
+// ErrNoMatches is returned by Fit when there are no results to fit, or when
+// none of them had a goal, as the strengths cannot be estimated from 0-0s.
+var ErrNoMatches = errors.New("goalmodel: no matches with goals to fit")
+
+type Match struct {
+	HomeTeam  int
+	AwayTeam  int
+	HomeGoals int
+	AwayGoals int
+	PlayedAt  time.Time
+}
+
+type Options struct {
+	// HalfLife is how long it takes for the weight of a result to halve.
+	HalfLife time.Duration
+	// Prior is the number of average matches added to every team's record,
+	// which pulls the strengths of teams with few results towards average.
+	Prior      float64
+	Iterations int
+}
+
+var DefaultOptions = Options{
+	HalfLife:   180 * 24 * time.Hour,
+	Prior:      1,
+	Iterations: 100,
+}
+
+type Model struct {
+	Attack        map[int]float64
+	Defence       map[int]float64
+	HomeAdvantage float64
+	Rho           float64
+	Matches       int
+	FittedAt      time.Time
+}
+
+// Fit estimates team strengths from results played before at. The strengths
+// are found by iterating the maximum likelihood equations for a weighted
+// Poisson model, after which rho is chosen to maximise the Dixon-Coles
+// likelihood with the strengths held fixed.
+func Fit(matches []Match, at time.Time, opts Options) (*Model, error) {
+	xi := math.Ln2 / opts.HalfLife.Hours()
+
+	var (
+		weights = make([]float64, 0, len(matches))
+		used    = make([]Match, 0, len(matches))
+	)
+
+	for _, match := range matches {
+		if !match.PlayedAt.Before(at) || match.HomeGoals < 0 || match.AwayGoals < 0 {
+			continue
+		}
+
+		used = append(used, match)
+		weights = append(weights, math.Exp(-xi*at.Sub(match.PlayedAt).Hours()))
+	}
+
+	if len(used) == 0 {
+		return nil, ErrNoMatches
+	}
+
+	model := &Model{
+		Attack:        map[int]float64{},
+		Defence:       map[int]float64{},
+		HomeAdvantage: 1,
+		Matches:       len(used),
+		FittedAt:      at,
+	}
+
+	for _, match := range used {
+		for _, team := range []int{match.HomeTeam, match.AwayTeam} {
+			model.Attack[team] = 1
+			model.Defence[team] = 1
+		}
+	}
+
+	var totalWeight, homeGoals, awayGoals float64
+	for i, match := range used {
+		totalWeight += weights[i]
+		homeGoals += weights[i] * float64(match.HomeGoals)
+		awayGoals += weights[i] * float64(match.AwayGoals)
+	}
+
+	if homeGoals+awayGoals == 0 {
+		return nil, ErrNoMatches
+	}
+
+	// The prior adds matches at the league average, which is what a team with
+	// no results of its own is assumed to be.
+	priorGoals := opts.Prior * (homeGoals + awayGoals) / (2 * totalWeight)
+
+	for iteration := 0; iteration < opts.Iterations; iteration++ {
+		scored := map[int]float64{}
+		conceded := map[int]float64{}
+		attackExposure := map[int]float64{}
+		defenceExposure := map[int]float64{}
+
+		for i, match := range used {
+			w := weights[i]
+			h := model.HomeAdvantage
+
+			scored[match.HomeTeam] += w * float64(match.HomeGoals)
+			scored[match.AwayTeam] += w * float64(match.AwayGoals)
+			conceded[match.HomeTeam] += w * float64(match.AwayGoals)
+			conceded[match.AwayTeam] += w * float64(match.HomeGoals)
+
+			attackExposure[match.HomeTeam] += w * model.Defence[match.AwayTeam] * h
+			attackExposure[match.AwayTeam] += w * model.Defence[match.HomeTeam]
+			defenceExposure[match.HomeTeam] += w * model.Attack[match.AwayTeam]
+			defenceExposure[match.AwayTeam] += w * model.Attack[match.HomeTeam] * h
+		}
+
+		for team := range model.Attack {
+			model.Attack[team] = (scored[team] + priorGoals) / (attackExposure[team] + opts.Prior)
+			model.Defence[team] = (conceded[team] + priorGoals) / (defenceExposure[team] + opts.Prior)
+		}
+
+		// Attack and defence are only identified up to a constant factor, so
+		// fix the average attack at one.
+		var meanAttack float64
+		for _, attack := range model.Attack {
+			meanAttack += attack
+		}
+		meanAttack /= float64(len(model.Attack))
+
+		for team := range model.Attack {
+			model.Attack[team] /= meanAttack
+			model.Defence[team] *= meanAttack
+		}
+
+		var homeExposure float64
+		for i, match := range used {
+			homeExposure += weights[i] * model.Attack[match.HomeTeam] * model.Defence[match.AwayTeam]
+		}
+
+		model.HomeAdvantage = homeGoals / homeExposure
+	}
+
+	model.Rho = fitRho(model, used, weights)
+
+	return model, nil
+}
+
+// fitRho searches for the rho that maximises the weighted likelihood of the
+// low scoring results, keeping rho within the range where tau stays positive
+// for every match.
+func fitRho(model *Model, matches []Match, weights []float64) float64 {
+	bestRho, bestLikelihood := 0.0, math.Inf(-1)
+
+	for rho := -0.25; rho <= 0.25; rho += 0.0025 {
+		var likelihood float64
+
+		for i, match := range matches {
+			lambda, mu := model.expectedGoals(match.HomeTeam, match.AwayTeam)
+
+			t := tau(match.HomeGoals, match.AwayGoals, lambda, mu, rho)
+			if t <= 0 {
+				likelihood = math.Inf(-1)
+				break
+			}
+
+			likelihood += weights[i] * math.Log(t)
+		}
+
+		if likelihood > bestLikelihood {
+			bestRho, bestLikelihood = rho, likelihood
+		}
+	}
+
+	return bestRho
+}
+
+func (m *Model) expectedGoals(home, away int) (float64, float64) {
+	return m.Attack[home] * m.Defence[away] * m.HomeAdvantage, m.Attack[away] * m.Defence[home]
+}
+
+// Knows reports whether the model has results for a team.
+func (m *Model) Knows(team int) bool {
+	_, ok := m.Attack[team]
+	return ok
+}
+
+// Predict returns the score probabilities for a match between two teams. It
+// returns false if either team has no results in the model.
+func (m *Model) Predict(home, away int) (*Prediction, bool) {
+	if !m.Knows(home) || !m.Knows(away) {
+		return nil, false
+	}
+
+	lambda, mu := m.expectedGoals(home, away)
+
+	return NewPrediction(lambda, mu, m.Rho), true
+}
+
+// Prediction holds the probability of each score, indexed by home goals and
+// then away goals.
+type Prediction struct {
+	HomeExpected float64
+	AwayExpected float64
+	Scores       [MaxGoals + 1][MaxGoals + 1]float64
+}
+
+func NewPrediction(lambda, mu, rho float64) *Prediction {
+	p := &Prediction{HomeExpected: lambda, AwayExpected: mu}
+
+	var total float64
+
+	for x := 0; x <= MaxGoals; x++ {
+		for y := 0; y <= MaxGoals; y++ {
+			probability := math.Max(tau(x, y, lambda, mu, rho), 0) * poisson(x, lambda) * poisson(y, mu)
+			p.Scores[x][y] = probability
+			total += probability
+		}
+	}
+
+	for x := range p.Scores {
+		for y := range p.Scores[x] {
+			p.Scores[x][y] /= total
+		}
+	}
+
+	return p
+}
+
+// Outcomes returns the probability of each way that a selection can be
+// settled, by settling every score with market.Settle. Quarter lines therefore
+// have half wins and half losses, and whole lines have pushes.
+func (p *Prediction) Outcomes(m market.Market, selection string, line float64) (map[market.Outcome]float64, error) {
+	outcomes := map[market.Outcome]float64{}
+
+	for x := range p.Scores {
+		for y, probability := range p.Scores[x] {
+			outcome, err := market.Settle(m, selection, line, x, y)
+			if err != nil {
+				return nil, err
+			}
+
+			outcomes[outcome] += probability
+		}
+	}
+
+	return outcomes, nil
+}
+
+// FairOdds returns the decimal odds at which a selection has an expected
+// profit of zero, so half wins, half losses and pushes are priced correctly.
+// It returns false if the selection can never win.
+func (p *Prediction) FairOdds(m market.Market, selection string, line float64) (float64, bool, error) {
+	outcomes, err := p.Outcomes(m, selection, line)
+	if err != nil {
+		return 0, false, err
+	}
+
+	// With odds o, the expected profit is won*o - stake, where won is the
+	// probability weighted share of the stake paid at the odds and stake is
+	// the share of the stake at risk.
+	won := outcomes[market.Won] + outcomes[market.HalfWon]/2
+	stake := won + outcomes[market.Lost] + outcomes[market.HalfLost]/2
+
+	if won == 0 {
+		return 0, false, nil
+	}
+
+	return stake / won, true, nil
+}
+
+// Probability returns the chance that a selection wins outright, counting
+// half wins as half.
+func (p *Prediction) Probability(m market.Market, selection string, line float64) (float64, error) {
+	outcomes, err := p.Outcomes(m, selection, line)
+	if err != nil {
+		return 0, err
+	}
+
+	return outcomes[market.Won] + outcomes[market.HalfWon]/2, nil
+}
+
+func tau(x, y int, lambda, mu, rho float64) float64 {
+	switch {
+	case x == 0 && y == 0:
+		return 1 - lambda*mu*rho
+	case x == 0 && y == 1:
+		return 1 + lambda*rho
+	case x == 1 && y == 0:
+		return 1 + mu*rho
+	case x == 1 && y == 1:
+		return 1 - rho
+	}
+
+	return 1
+}
+
+func poisson(k int, lambda float64) float64 {
+	if lambda == 0 {
+		if k == 0 {
+			return 1
+		}
+		return 0
+	}
+
+	lgamma, _ := math.Lgamma(float64(k + 1))
+	return math.Exp(float64(k)*math.Log(lambda) - lambda - lgamma)
+}
@@ -0,0 +1,168 @@
+package goalmodel
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/afoejoe/football-predict/internal/market"
+)
+
+func TestFitWithoutGoals(t *testing.T) {
+	at := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		matches []Match
+	}{
+		{"no matches", nil},
+		{"only goalless draws", []Match{
+			{HomeTeam: 1, AwayTeam: 2, PlayedAt: at.AddDate(0, 0, -14)},
+			{HomeTeam: 2, AwayTeam: 1, PlayedAt: at.AddDate(0, 0, -7)},
+		}},
+		{"goals after the fitting date", []Match{
+			{HomeTeam: 1, AwayTeam: 2, HomeGoals: 2, AwayGoals: 1, PlayedAt: at},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Fit(tt.matches, at, DefaultOptions)
+			if !errors.Is(err, ErrNoMatches) {
+				t.Errorf("got error %v; want %v", err, ErrNoMatches)
+			}
+		})
+	}
+}
+
+// league plays a double round robin between four teams in which team 1
+// scores three against everyone, team 4 scores none and the others score one.
+func league(at time.Time) []Match {
+	goals := map[int]int{1: 3, 2: 1, 3: 1, 4: 0}
+
+	var matches []Match
+
+	day := 1
+	for home := 1; home <= 4; home++ {
+		for away := 1; away <= 4; away++ {
+			if home == away {
+				continue
+			}
+
+			matches = append(matches, Match{
+				HomeTeam:  home,
+				AwayTeam:  away,
+				HomeGoals: goals[home],
+				AwayGoals: goals[away],
+				PlayedAt:  at.AddDate(0, 0, -day),
+			})
+			day++
+		}
+	}
+
+	return matches
+}
+
+func TestFitRanksTeams(t *testing.T) {
+	at := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	model, err := Fit(league(at), at, DefaultOptions)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if model.Matches != 12 {
+		t.Errorf("got %d matches; want 12", model.Matches)
+	}
+
+	if !(model.Attack[1] > model.Attack[2] && model.Attack[2] > model.Attack[4]) {
+		t.Errorf("attack strengths are not ordered by goals scored: %v", model.Attack)
+	}
+
+	if model.Knows(5) {
+		t.Error("the model should not know a team without results")
+	}
+
+	if _, ok := model.Predict(1, 5); ok {
+		t.Error("the model should not predict a match with an unknown team")
+	}
+
+	strong, _ := model.Predict(1, 4)
+	weak, _ := model.Predict(4, 1)
+
+	if strong.HomeExpected <= weak.HomeExpected {
+		t.Errorf("team 1 at home expects %v goals; want more than team 4's %v", strong.HomeExpected, weak.HomeExpected)
+	}
+}
+
+func TestPredictionProbabilities(t *testing.T) {
+	tests := []struct {
+		name   string
+		lambda float64
+		mu     float64
+		rho    float64
+	}{
+		{"average", 1.5, 1.1, 0},
+		{"with rho", 1.5, 1.1, -0.1},
+		{"lopsided", 3.2, 0.4, 0.05},
+		{"goalless side", 1.2, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewPrediction(tt.lambda, tt.mu, tt.rho)
+
+			var total float64
+			for x := range p.Scores {
+				for _, probability := range p.Scores[x] {
+					total += probability
+				}
+			}
+
+			if math.Abs(total-1) > 1e-9 {
+				t.Errorf("score matrix sums to %v; want 1", total)
+			}
+
+			var matchResult float64
+			for _, selection := range []string{market.Home, market.Draw, market.Away} {
+				probability, err := p.Probability(market.MatchResult, selection, 0)
+				if err != nil {
+					t.Fatal(err)
+				}
+				matchResult += probability
+			}
+
+			if math.Abs(matchResult-1) > 1e-9 {
+				t.Errorf("1X2 probabilities sum to %v; want 1", matchResult)
+			}
+		})
+	}
+}
+
+func TestRhoAdjustsLowScores(t *testing.T) {
+	independent := NewPrediction(1.4, 1.1, 0)
+	adjusted := NewPrediction(1.4, 1.1, -0.1)
+
+	// A negative rho makes 0-0 and 1-1 more likely and 1-0 and 0-1 less likely.
+	if adjusted.Scores[0][0] <= independent.Scores[0][0] {
+		t.Errorf("0-0: got %v; want more than %v", adjusted.Scores[0][0], independent.Scores[0][0])
+	}
+	if adjusted.Scores[1][1] <= independent.Scores[1][1] {
+		t.Errorf("1-1: got %v; want more than %v", adjusted.Scores[1][1], independent.Scores[1][1])
+	}
+	if adjusted.Scores[1][0] >= independent.Scores[1][0] {
+		t.Errorf("1-0: got %v; want less than %v", adjusted.Scores[1][0], independent.Scores[1][0])
+	}
+	if adjusted.Scores[0][1] >= independent.Scores[0][1] {
+		t.Errorf("0-1: got %v; want less than %v", adjusted.Scores[0][1], independent.Scores[0][1])
+	}
+
+	// Higher scores are only rescaled, so their ratios are unchanged.
+	ratio := func(p *Prediction) float64 {
+		return p.Scores[3][2] / p.Scores[2][3]
+	}
+	if math.Abs(ratio(adjusted)-ratio(independent)) > 1e-9 {
+		t.Errorf("rho changed the ratio of 3-2 to 2-3")
+	}
+}
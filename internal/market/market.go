@@ -0,0 +1,158 @@
+package market
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+type Market string
+
+const (
+	MatchResult      Market = "1x2"
+	OverUnder        Market = "over_under"
+	BothTeamsToScore Market = "btts"
+	AsianHandicap    Market = "asian_handicap"
+	CorrectScore     Market = "correct_score"
+	DoubleChance     Market = "double_chance"
+)
+
+var Markets = []Market{MatchResult, OverUnder, BothTeamsToScore, AsianHandicap, CorrectScore, DoubleChance}
+
+const (
+	Home       = "home"
+	Draw       = "draw"
+	Away       = "away"
+	Over       = "over"
+	Under      = "under"
+	Yes        = "yes"
+	No         = "no"
+	HomeOrDraw = "1x"
+	HomeOrAway = "12"
+	DrawOrAway = "x2"
+)
+
+var RgxScore = regexp.MustCompile(`^(\d{1,2})-(\d{1,2})$`)
+
+func (m Market) Name() string {
+	switch m {
+	case MatchResult:
+		return "Match result (1X2)"
+	case OverUnder:
+		return "Total goals over/under"
+	case BothTeamsToScore:
+		return "Both teams to score"
+	case AsianHandicap:
+		return "Asian handicap"
+	case CorrectScore:
+		return "Correct score"
+	case DoubleChance:
+		return "Double chance"
+	}
+
+	return string(m)
+}
+
+// Selections returns the allowed selections for the market. Correct score
+// selections are free-form scores and are checked against RgxScore instead.
+func (m Market) Selections() []string {
+	switch m {
+	case MatchResult:
+		return []string{Home, Draw, Away}
+	case OverUnder:
+		return []string{Over, Under}
+	case BothTeamsToScore:
+		return []string{Yes, No}
+	case AsianHandicap:
+		return []string{Home, Away}
+	case DoubleChance:
+		return []string{HomeOrDraw, HomeOrAway, DrawOrAway}
+	}
+
+	return nil
+}
+
+func (m Market) HasLine() bool {
+	return m == OverUnder || m == AsianHandicap
+}
+
+func Label(m Market, selection string, line float64) string {
+	switch m {
+	case MatchResult:
+		switch selection {
+		case Home:
+			return "Home win"
+		case Draw:
+			return "Draw"
+		case Away:
+			return "Away win"
+		}
+	case OverUnder:
+		switch selection {
+		case Over:
+			return "Over " + FormatLine(line, false)
+		case Under:
+			return "Under " + FormatLine(line, false)
+		}
+	case BothTeamsToScore:
+		switch selection {
+		case Yes:
+			return "BTTS Yes"
+		case No:
+			return "BTTS No"
+		}
+	case AsianHandicap:
+		switch selection {
+		case Home:
+			return "Home " + FormatLine(line, true)
+		case Away:
+			return "Away " + FormatLine(line, true)
+		}
+	case CorrectScore:
+		return "Correct score " + selection
+	case DoubleChance:
+		switch selection {
+		case HomeOrDraw:
+			return "Home or draw"
+		case HomeOrAway:
+			return "Home or away"
+		case DrawOrAway:
+			return "Draw or away"
+		}
+	}
+
+	return strings.TrimSpace(fmt.Sprintf("%s %s", m.Name(), selection))
+}
+
+// FormatLine formats a goal line using the shortest representation, with an
+// explicit sign for handicaps (e.g. "2.5", "-0.75", "+1", "0").
+func FormatLine(line float64, signed bool) string {
+	s := strconv.FormatFloat(line, 'f', -1, 64)
+
+	if signed && line > 0 {
+		return "+" + s
+	}
+
+	return s
+}
+
+// ParseScore parses a correct score selection such as "2-1".
+func ParseScore(selection string) (home, away int, ok bool) {
+	matches := RgxScore.FindStringSubmatch(selection)
+	if matches == nil {
+		return 0, 0, false
+	}
+
+	home, _ = strconv.Atoi(matches[1])
+	away, _ = strconv.Atoi(matches[2])
+
+	return home, away, true
+}
+
+// IsQuarterLine reports whether the line is a whole, half or quarter goal line.
+func IsQuarterLine(line float64) bool {
+	quarters := line * 4
+	return quarters == math.Trunc(quarters)
+}
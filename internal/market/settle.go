@@ -0,0 +1,177 @@
+package market
+
+import (
+	"fmt"
+	"math"
+)
+
+type Outcome string
+
+const (
+	Pending  Outcome = "pending"
+	Won      Outcome = "won"
+	HalfWon  Outcome = "half_won"
+	Push     Outcome = "push"
+	Void     Outcome = "void"
+	HalfLost Outcome = "half_lost"
+	Lost     Outcome = "lost"
+)
+
+var Outcomes = []Outcome{Pending, Won, HalfWon, Push, Void, HalfLost, Lost}
+
+func (o Outcome) Settled() bool {
+	return o != Pending && o != ""
+}
+
+func (o Outcome) Label() string {
+	switch o {
+	case Won:
+		return "Won"
+	case HalfWon:
+		return "Half won"
+	case Push:
+		return "Push"
+	case Void:
+		return "Void"
+	case HalfLost:
+		return "Half lost"
+	case Lost:
+		return "Lost"
+	}
+
+	return "Pending"
+}
+
+// Profit returns the profit or loss for a one unit stake at the given decimal
+// odds. Half outcomes come from quarter lines, where the stake is split
+// equally across the two neighbouring half lines.
+func (o Outcome) Profit(odds float64) float64 {
+	switch o {
+	case Won:
+		return odds - 1
+	case HalfWon:
+		return (odds - 1) / 2
+	case HalfLost:
+		return -0.5
+	case Lost:
+		return -1
+	}
+
+	return 0
+}
+
+// Settle evaluates a selection against the final score of a fixture.
+func Settle(m Market, selection string, line float64, homeGoals, awayGoals int) (Outcome, error) {
+	if homeGoals < 0 || awayGoals < 0 {
+		return Pending, fmt.Errorf("market: invalid score %d-%d", homeGoals, awayGoals)
+	}
+
+	switch m {
+	case MatchResult:
+		switch selection {
+		case Home:
+			return wonIf(homeGoals > awayGoals), nil
+		case Draw:
+			return wonIf(homeGoals == awayGoals), nil
+		case Away:
+			return wonIf(homeGoals < awayGoals), nil
+		}
+
+	case DoubleChance:
+		switch selection {
+		case HomeOrDraw:
+			return wonIf(homeGoals >= awayGoals), nil
+		case HomeOrAway:
+			return wonIf(homeGoals != awayGoals), nil
+		case DrawOrAway:
+			return wonIf(homeGoals <= awayGoals), nil
+		}
+
+	case BothTeamsToScore:
+		switch selection {
+		case Yes:
+			return wonIf(homeGoals > 0 && awayGoals > 0), nil
+		case No:
+			return wonIf(homeGoals == 0 || awayGoals == 0), nil
+		}
+
+	case CorrectScore:
+		home, away, ok := ParseScore(selection)
+		if ok {
+			return wonIf(home == homeGoals && away == awayGoals), nil
+		}
+
+	case OverUnder:
+		if !IsQuarterLine(line) {
+			return Pending, fmt.Errorf("market: invalid line %v", line)
+		}
+
+		total := homeGoals + awayGoals
+
+		switch selection {
+		case Over:
+			return settleLine(total, line), nil
+		case Under:
+			return settleLine(-total, -line), nil
+		}
+
+	case AsianHandicap:
+		if !IsQuarterLine(line) {
+			return Pending, fmt.Errorf("market: invalid line %v", line)
+		}
+
+		switch selection {
+		case Home:
+			return settleLine(homeGoals-awayGoals, -line), nil
+		case Away:
+			return settleLine(awayGoals-homeGoals, -line), nil
+		}
+	}
+
+	return Pending, fmt.Errorf("market: invalid selection %q for market %q", selection, m)
+}
+
+func wonIf(ok bool) Outcome {
+	if ok {
+		return Won
+	}
+
+	return Lost
+}
+
+// settleLine settles a bet that wins when goals is strictly greater than the
+// line. Quarter lines are split into the two neighbouring half or whole lines
+// and the two halves are combined. Everything is worked out in quarter goals
+// so that the comparisons are exact.
+func settleLine(goals int, line float64) Outcome {
+	quarters := int(math.Round(line * 4))
+
+	if quarters%2 == 0 {
+		return compareQuarters(goals*4, quarters)
+	}
+
+	lower := compareQuarters(goals*4, quarters-1)
+	upper := compareQuarters(goals*4, quarters+1)
+
+	switch {
+	case lower == upper:
+		return lower
+	case lower == Won && upper == Push:
+		return HalfWon
+	case lower == Push && upper == Lost:
+		return HalfLost
+	}
+
+	return Pending
+}
+
+func compareQuarters(goals, line int) Outcome {
+	switch {
+	case goals > line:
+		return Won
+	case goals < line:
+		return Lost
+	}
+
+	return Push
+}
@@ -0,0 +1,181 @@
+package market
+
+import (
+	"fmt"
+	"testing"
+)
+
+type settleTest struct {
+	selection string
+	line      float64
+	home      int
+	away      int
+	want      Outcome
+}
+
+func runSettleTests(t *testing.T, m Market, tests []settleTest) {
+	t.Helper()
+
+	for _, tt := range tests {
+		name := fmt.Sprintf("%s %v %d-%d", tt.selection, tt.line, tt.home, tt.away)
+
+		t.Run(name, func(t *testing.T) {
+			got, err := Settle(m, tt.selection, tt.line, tt.home, tt.away)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got != tt.want {
+				t.Errorf("got %q; want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSettleMatchResult(t *testing.T) {
+	runSettleTests(t, MatchResult, []settleTest{
+		{Home, 0, 2, 1, Won},
+		{Home, 0, 1, 1, Lost},
+		{Home, 0, 0, 1, Lost},
+		{Draw, 0, 1, 1, Won},
+		{Draw, 0, 0, 0, Won},
+		{Draw, 0, 2, 1, Lost},
+		{Away, 0, 0, 3, Won},
+		{Away, 0, 2, 2, Lost},
+		{Away, 0, 1, 0, Lost},
+	})
+}
+
+func TestSettleBothTeamsToScore(t *testing.T) {
+	runSettleTests(t, BothTeamsToScore, []settleTest{
+		{Yes, 0, 1, 1, Won},
+		{Yes, 0, 3, 2, Won},
+		{Yes, 0, 1, 0, Lost},
+		{Yes, 0, 0, 0, Lost},
+		{No, 0, 0, 0, Won},
+		{No, 0, 0, 2, Won},
+		{No, 0, 1, 1, Lost},
+	})
+}
+
+func TestSettleDoubleChance(t *testing.T) {
+	runSettleTests(t, DoubleChance, []settleTest{
+		{HomeOrDraw, 0, 2, 1, Won},
+		{HomeOrDraw, 0, 1, 1, Won},
+		{HomeOrDraw, 0, 0, 1, Lost},
+		{DrawOrAway, 0, 2, 1, Lost},
+		{DrawOrAway, 0, 1, 1, Won},
+		{DrawOrAway, 0, 0, 1, Won},
+		{HomeOrAway, 0, 2, 1, Won},
+		{HomeOrAway, 0, 1, 1, Lost},
+		{HomeOrAway, 0, 0, 1, Won},
+	})
+}
+
+func TestSettleCorrectScore(t *testing.T) {
+	runSettleTests(t, CorrectScore, []settleTest{
+		{"2-1", 0, 2, 1, Won},
+		{"0-0", 0, 0, 0, Won},
+		{"10-0", 0, 10, 0, Won},
+		{"2-1", 0, 1, 2, Lost},
+		{"2-1", 0, 2, 2, Lost},
+		{"0-0", 0, 1, 0, Lost},
+	})
+}
+
+func TestSettleOverUnder(t *testing.T) {
+	runSettleTests(t, OverUnder, []settleTest{
+		// Half lines.
+		{Over, 2.5, 2, 1, Won},
+		{Over, 2.5, 1, 1, Lost},
+		{Under, 2.5, 1, 1, Won},
+		{Under, 2.5, 2, 1, Lost},
+
+		// Whole lines push when the total lands on the line.
+		{Over, 2, 1, 1, Push},
+		{Under, 2, 2, 0, Push},
+		{Over, 2, 2, 1, Won},
+		{Under, 2, 2, 1, Lost},
+
+		// Quarter lines split the stake across the neighbouring lines.
+		{Over, 2.25, 1, 1, HalfLost},
+		{Over, 2.25, 2, 1, Won},
+		{Under, 2.25, 1, 1, HalfWon},
+		{Under, 2.25, 2, 1, Lost},
+		{Over, 2.75, 2, 1, HalfWon},
+		{Over, 2.75, 1, 1, Lost},
+		{Under, 2.75, 2, 1, HalfLost},
+		{Under, 2.75, 1, 1, Won},
+	})
+}
+
+func TestSettleAsianHandicap(t *testing.T) {
+	runSettleTests(t, AsianHandicap, []settleTest{
+		// Level ball and whole lines.
+		{Home, 0, 1, 1, Push},
+		{Away, 0, 1, 1, Push},
+		{Home, 0, 2, 1, Won},
+		{Away, 0, 2, 1, Lost},
+		{Home, -1, 2, 1, Push},
+		{Home, -1, 3, 1, Won},
+		{Away, 1, 2, 1, Push},
+		{Away, 1, 1, 1, Won},
+
+		// Half lines never push.
+		{Home, -0.5, 1, 0, Won},
+		{Home, -0.5, 1, 1, Lost},
+		{Away, 0.5, 1, 1, Won},
+		{Away, 0.5, 1, 0, Lost},
+
+		// -0.25 is split across 0 and -0.5.
+		{Home, -0.25, 1, 1, HalfLost},
+		{Home, -0.25, 1, 0, Won},
+		{Away, -0.25, 1, 1, HalfLost},
+
+		// +0.25 is split across 0 and +0.5.
+		{Home, 0.25, 1, 1, HalfWon},
+		{Home, 0.25, 0, 1, Lost},
+		{Away, 0.25, 1, 1, HalfWon},
+
+		// -0.75 is split across -0.5 and -1.
+		{Home, -0.75, 1, 0, HalfWon},
+		{Home, -0.75, 2, 0, Won},
+		{Home, -0.75, 1, 1, Lost},
+		{Away, -0.75, 0, 1, HalfWon},
+
+		// +0.75 is split across +0.5 and +1.
+		{Home, 0.75, 0, 1, HalfLost},
+		{Home, 0.75, 1, 1, Won},
+		{Home, 0.75, 0, 2, Lost},
+		{Away, 0.75, 1, 0, HalfLost},
+	})
+}
+
+func TestSettleErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		market    Market
+		selection string
+		line      float64
+		home      int
+		away      int
+	}{
+		{"negative score", MatchResult, Home, 0, -1, 0},
+		{"unknown selection", MatchResult, Over, 0, 1, 0},
+		{"double chance selection from another market", DoubleChance, Home, 0, 1, 0},
+		{"correct score without a dash", CorrectScore, "21", 0, 2, 1},
+		{"correct score with words", CorrectScore, "two-one", 0, 2, 1},
+		{"correct score with three digits", CorrectScore, "100-0", 0, 1, 0},
+		{"line not a quarter", OverUnder, Over, 2.3, 1, 0},
+		{"handicap line not a quarter", AsianHandicap, Home, 0.1, 1, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Settle(tt.market, tt.selection, tt.line, tt.home, tt.away)
+			if err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,191 @@
+// Package odds converts decimal odds, which is how prices are stored, to the
+// formats that punters in different countries are used to reading.
+package odds
+
+import (
+	"math"
+	"strconv"
+
+	"golang.org/x/text/language"
+)
+
+type Format string
+
+const (
+	FormatDecimal    Format = "decimal"
+	FormatFractional Format = "fractional"
+	FormatAmerican   Format = "american"
+	FormatHongKong   Format = "hong_kong"
+	FormatImplied    Format = "implied"
+)
+
+var Formats = []Format{FormatDecimal, FormatFractional, FormatAmerican, FormatHongKong, FormatImplied}
+
+func (f Format) Name() string {
+	switch f {
+	case FormatDecimal:
+		return "Decimal (2.50)"
+	case FormatFractional:
+		return "Fractional (6/4)"
+	case FormatAmerican:
+		return "American (+150)"
+	case FormatHongKong:
+		return "Hong Kong (1.50)"
+	case FormatImplied:
+		return "Probability (40%)"
+	default:
+		return string(f)
+	}
+}
+
+// Display formats decimal odds in the given format. Unknown formats fall back
+// to decimal.
+func Display(format Format, decimal float64) string {
+	switch format {
+	case FormatFractional:
+		return Fractional(decimal)
+	case FormatAmerican:
+		return American(decimal)
+	case FormatHongKong:
+		return HongKong(decimal)
+	case FormatImplied:
+		return Implied(decimal)
+	default:
+		return Decimal(decimal)
+	}
+}
+
+func Decimal(decimal float64) string {
+	return strconv.FormatFloat(decimal, 'f', 2, 64)
+}
+
+// ladder holds the fractional prices that bookmakers quote, which are not
+// always in their lowest terms: 6/4 is used rather than 3/2, for example.
+var ladder = [][2]int{
+	{1, 20}, {1, 16}, {1, 14}, {1, 12}, {1, 11}, {1, 10}, {1, 9}, {1, 8}, {1, 7}, {1, 6},
+	{1, 5}, {2, 9}, {1, 4}, {2, 7}, {3, 10}, {1, 3}, {4, 11}, {2, 5}, {4, 9}, {1, 2},
+	{8, 15}, {4, 7}, {8, 13}, {4, 6}, {8, 11}, {4, 5}, {5, 6}, {10, 11}, {1, 1}, {21, 20},
+	{11, 10}, {6, 5}, {5, 4}, {11, 8}, {6, 4}, {13, 8}, {7, 4}, {15, 8}, {2, 1}, {85, 40},
+	{9, 4}, {5, 2}, {11, 4}, {3, 1}, {10, 3}, {7, 2}, {4, 1}, {9, 2}, {5, 1}, {11, 2},
+	{6, 1}, {13, 2}, {7, 1}, {15, 2}, {8, 1}, {17, 2}, {9, 1}, {10, 1}, {11, 1}, {12, 1},
+	{14, 1}, {16, 1}, {18, 1}, {20, 1}, {25, 1}, {33, 1}, {40, 1}, {50, 1}, {66, 1}, {100, 1},
+}
+
+// Fractional returns the profit to a stake of one as a fraction, such as 6/4
+// for decimal odds of 2.50. Decimal odds are stored to two decimal places, so
+// any fraction within half a hundredth is acceptable. The closest price on the
+// bookmakers' ladder is preferred, then the closest fraction with a
+// denominator up to 20, and anything else is reduced from the decimal price.
+// Odds of 1 or less, or too close to 1 to show in hundredths, have no
+// fractional form.
+func Fractional(decimal float64) string {
+	const tolerance = 0.005 + 1e-9
+
+	if decimal <= 1 {
+		return "-"
+	}
+
+	profit := decimal - 1
+
+	var numerator, denominator int
+
+	bestError := tolerance
+	for _, fraction := range ladder {
+		e := math.Abs(float64(fraction[0])/float64(fraction[1]) - profit)
+		if e < bestError {
+			numerator, denominator, bestError = fraction[0], fraction[1], e
+		}
+	}
+
+	if denominator == 0 {
+		for d := 1; d <= 20; d++ {
+			n := int(math.Round(profit * float64(d)))
+			e := math.Abs(float64(n)/float64(d) - profit)
+			if n > 0 && e < bestError {
+				numerator, denominator, bestError = n, d, e
+			}
+		}
+	}
+
+	if denominator == 0 {
+		numerator, denominator = int(math.Round(profit*100)), 100
+		if numerator == 0 {
+			return "-"
+		}
+
+		divisor := gcd(numerator, denominator)
+		numerator, denominator = numerator/divisor, denominator/divisor
+	}
+
+	if numerator == denominator {
+		return "Evens"
+	}
+
+	return strconv.Itoa(numerator) + "/" + strconv.Itoa(denominator)
+}
+
+// American returns moneyline odds: the profit to a stake of 100 for prices of
+// evens or longer, and the stake needed to win 100 for shorter prices.
+func American(decimal float64) string {
+	if decimal >= 2 {
+		return "+" + strconv.Itoa(int(math.Round((decimal-1)*100)))
+	}
+
+	if decimal <= 1 {
+		return "-"
+	}
+
+	return "-" + strconv.Itoa(int(math.Round(100/(decimal-1))))
+}
+
+// HongKong returns the profit to a stake of one, as a decimal.
+func HongKong(decimal float64) string {
+	return strconv.FormatFloat(decimal-1, 'f', 2, 64)
+}
+
+// Implied returns the probability that the odds imply, as a percentage.
+func Implied(decimal float64) string {
+	if decimal <= 0 {
+		return "-"
+	}
+
+	return strconv.FormatFloat(100/decimal, 'f', 1, 64) + "%"
+}
+
+// DefaultFormat picks a format for a visitor from their Accept-Language header,
+// using the regions where each format is the norm. Only regions that the
+// visitor states explicitly are used, so a plain "en" gets decimal odds.
+func DefaultFormat(acceptLanguage string) Format {
+	tags, _, err := language.ParseAcceptLanguage(acceptLanguage)
+	if err != nil || len(tags) == 0 {
+		return FormatDecimal
+	}
+
+	region, confidence := tags[0].Region()
+	if confidence != language.Exact {
+		return FormatDecimal
+	}
+
+	switch region.String() {
+	case "GB", "IE":
+		return FormatFractional
+	case "US":
+		return FormatAmerican
+	case "HK":
+		return FormatHongKong
+	default:
+		return FormatDecimal
+	}
+}
+
+func gcd(a, b int) int {
+	for b != 0 {
+		a, b = b, a%b
+	}
+
+	if a == 0 {
+		return 1
+	}
+
+	return a
+}
@@ -0,0 +1,33 @@
+package odds
+
+import "testing"
+
+func TestFractional(t *testing.T) {
+	tests := []struct {
+		decimal float64
+		want    string
+	}{
+		{2.5, "6/4"},
+		{2, "Evens"},
+		{1.5, "1/2"},
+		{1.91, "10/11"},
+		{3.25, "9/4"},
+		{11, "10/1"},
+		{1.05, "1/20"},
+		{1.62, "8/13"},
+		{1.01, "1/100"},
+		{1.004, "-"},
+		{1.001, "-"},
+		{1, "-"},
+		{0.5, "-"},
+		{0, "-"},
+	}
+
+	for _, tt := range tests {
+		got := Fractional(tt.decimal)
+
+		if got != tt.want {
+			t.Errorf("Fractional(%v) = %q; want %q", tt.decimal, got, tt.want)
+		}
+	}
+}
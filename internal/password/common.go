@@ -0,0 +1,12 @@
+package password
+
+// CommonPasswords is a short list of frequently used passwords that are
+// rejected at signup.
+var CommonPasswords = []string{
+	"12345678", "123456789", "1234567890", "password", "password1", "password123",
+	"qwertyuiop", "qwerty123", "11111111", "00000000", "12341234", "87654321",
+	"iloveyou", "football", "football1", "baseball", "sunshine", "princess",
+	"superman", "1q2w3e4r", "abc12345", "abcd1234", "letmein1", "welcome1",
+	"trustno1", "passw0rd", "liverpool", "chelsea1", "arsenal1", "manchester",
+	"barcelona", "realmadrid", "jordan23", "michael1", "starwars", "whatever",
+}
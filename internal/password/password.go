@@ -0,0 +1,36 @@
+package password
+
+import (
+	"errors"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// DummyHash is a hash with the same cost as Hash of a password that nobody
+// knows. Checking a password against it when there is no account takes as
+// long as checking a real one, so response times do not reveal which email
+// addresses are registered.
+const DummyHash = "$2a$12$9B6srD4pzqsnmARBB6Nd1uCLHk0pDBhX//YeGmQcoHbVmTP2/YP3m"
+
+func Hash(plaintextPassword string) (string, error) {
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(plaintextPassword), 12)
+	if err != nil {
+		return "", err
+	}
+
+	return string(hashedPassword), nil
+}
+
+func Matches(plaintextPassword, hashedPassword string) (bool, error) {
+	err := bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(plaintextPassword))
+	if err != nil {
+		switch {
+		case errors.Is(err, bcrypt.ErrMismatchedHashAndPassword):
+			return false, nil
+		default:
+			return false, err
+		}
+	}
+
+	return true, nil
+}
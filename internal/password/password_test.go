@@ -0,0 +1,23 @@
+package password
+
+import "testing"
+
+func TestDummyHash(t *testing.T) {
+	matches, err := Matches("password", DummyHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if matches {
+		t.Error("the dummy hash matched a password")
+	}
+
+	hashedPassword, err := Hash("correct horse battery staple")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if hashedPassword[:7] != DummyHash[:7] {
+		t.Errorf("the dummy hash has prefix %q; want the same algorithm and cost as %q", DummyHash[:7], hashedPassword[:7])
+	}
+}
@@ -0,0 +1,44 @@
+package signing
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestVerifyWithMaxAge(t *testing.T) {
+	const secretKey = "nsuxbx3k62czotvyzrxuh4nhgjsmi7z3"
+
+	issuedAt := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	signed := SignWithTime("newsletter-confirm", "42", secretKey, issuedAt)
+
+	tests := []struct {
+		name    string
+		signed  string
+		signAs  string
+		now     time.Time
+		want    string
+		wantErr error
+	}{
+		{"fresh", signed, "newsletter-confirm", issuedAt.Add(time.Hour), "42", nil},
+		{"at the limit", signed, "newsletter-confirm", issuedAt.Add(72 * time.Hour), "42", nil},
+		{"expired", signed, "newsletter-confirm", issuedAt.Add(72*time.Hour + time.Second), "", ErrExpired},
+		{"other purpose", signed, "newsletter-unsubscribe", issuedAt, "", ErrInvalidSignature},
+		{"tampered", signed + "3", "newsletter-confirm", issuedAt, "", ErrInvalidSignature},
+		{"without a time", Sign("newsletter-confirm", "42", secretKey), "newsletter-confirm", issuedAt, "", ErrInvalidSignature},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := VerifyWithMaxAge(tt.signAs, tt.signed, secretKey, 72*time.Hour, tt.now)
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got error %v; want %v", err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Errorf("got %q; want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -2,7 +2,6 @@ package smtp
 
 import (
 	"bytes"
-	"time"
 
 	"github.com/afoejoe/football-predict/assets"
 	"github.com/afoejoe/football-predict/internal/funcs"
@@ -13,90 +12,138 @@ import (
 	textTemplate "text/template"
 )
 
-const defaultTimeout = 10 * time.Second
+// Outbox stores rendered emails until they are delivered.
+type Outbox interface {
+	EnqueueEmail(recipient string, headers map[string]string, subject, plainBody, htmlBody string) error
+}
+
+type Message struct {
+	Recipient string
+	Headers   map[string]string
+	Subject   string
+	PlainBody string
+	HTMLBody  string
+}
 
 type Mailer struct {
-	client mail.Client
-	from   string
+	transport Transport
+	from      string
+	outbox    Outbox
+}
+
+// NewMailer returns a Mailer that delivers through transport. If outbox is nil
+// then Send delivers straight away instead of queueing, which is useful in
+// tests together with a MemoryTransport.
+func NewMailer(transport Transport, from string, outbox Outbox) *Mailer {
+	return &Mailer{
+		transport: transport,
+		from:      from,
+		outbox:    outbox,
+	}
 }
 
-func NewMailer(host string, port int, username, password, from string) (*Mailer, error) {
-	client, err := mail.NewClient(host, mail.WithTimeout(defaultTimeout), mail.WithSMTPAuth(mail.SMTPAuthLogin), mail.WithPort(port), mail.WithUsername(username), mail.WithPassword(password))
+// Send renders an email and adds it to the outbox. It is delivered later by
+// calling Deliver, so an unavailable SMTP server does not lose it.
+func (m *Mailer) Send(recipient string, data any, patterns ...string) error {
+	return m.SendWithHeaders(recipient, nil, data, patterns...)
+}
+
+// SendWithHeaders is like Send but also sets extra message headers, such as
+// List-Unsubscribe.
+func (m *Mailer) SendWithHeaders(recipient string, headers map[string]string, data any, patterns ...string) error {
+	msg, err := Render(recipient, headers, data, patterns...)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	mailer := &Mailer{
-		client: *client,
-		from:   from,
+	if m.outbox == nil {
+		return m.Deliver(msg)
 	}
 
-	return mailer, nil
+	return m.outbox.EnqueueEmail(msg.Recipient, msg.Headers, msg.Subject, msg.PlainBody, msg.HTMLBody)
 }
 
-func (m *Mailer) Send(recipient string, data any, patterns ...string) error {
+// Render executes the subject, plainBody and optional htmlBody templates in
+// the given email template files.
+func Render(recipient string, headers map[string]string, data any, patterns ...string) (*Message, error) {
+	fullPatterns := make([]string, len(patterns))
 	for i := range patterns {
-		patterns[i] = "emails/" + patterns[i]
-	}
-	msg := mail.NewMsg()
-
-	err := msg.To(recipient)
-	if err != nil {
-		return err
+		fullPatterns[i] = "emails/" + patterns[i]
 	}
 
-	err = msg.From(m.from)
-	if err != nil {
-		return err
+	msg := &Message{
+		Recipient: recipient,
+		Headers:   headers,
 	}
 
-	ts, err := textTemplate.New("").Funcs(funcs.TemplateFuncs).ParseFS(assets.EmbeddedFiles, patterns...)
+	ts, err := textTemplate.New("").Funcs(funcs.TemplateFuncs).ParseFS(assets.EmbeddedFiles, fullPatterns...)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	subject := new(bytes.Buffer)
 	err = ts.ExecuteTemplate(subject, "subject", data)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	msg.Subject(subject.String())
+	msg.Subject = subject.String()
 
 	plainBody := new(bytes.Buffer)
 	err = ts.ExecuteTemplate(plainBody, "plainBody", data)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	msg.SetBodyString(mail.TypeTextPlain, plainBody.String())
+	msg.PlainBody = plainBody.String()
 
 	if ts.Lookup("htmlBody") != nil {
-		ts, err := htmlTemplate.New("").Funcs(funcs.TemplateFuncs).ParseFS(assets.EmbeddedFiles, patterns...)
+		ts, err := htmlTemplate.New("").Funcs(funcs.TemplateFuncs).ParseFS(assets.EmbeddedFiles, fullPatterns...)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		htmlBody := new(bytes.Buffer)
 		err = ts.ExecuteTemplate(htmlBody, "htmlBody", data)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
-		msg.AddAlternativeString(mail.TypeTextHTML, htmlBody.String())
+		msg.HTMLBody = htmlBody.String()
 	}
 
-	for i := 1; i <= 3; i++ {
-		err = m.client.DialAndSend(msg)
+	return msg, nil
+}
 
-		if nil == err {
-			return nil
-		}
+// Deliver makes a single attempt to send a rendered email through the
+// transport. Retrying is left to the caller.
+func (m *Mailer) Deliver(msg *Message) error {
+	return m.transport.Deliver(m.from, msg)
+}
 
-		if i != 3 {
-			time.Sleep(2 * time.Second)
-		}
+func newMsg(from string, message *Message) (*mail.Msg, error) {
+	msg := mail.NewMsg()
+
+	err := msg.To(message.Recipient)
+	if err != nil {
+		return nil, err
+	}
+
+	err = msg.From(from)
+	if err != nil {
+		return nil, err
+	}
+
+	for key, value := range message.Headers {
+		msg.SetGenHeader(mail.Header(key), value)
+	}
+
+	msg.Subject(message.Subject)
+	msg.SetBodyString(mail.TypeTextPlain, message.PlainBody)
+
+	if message.HTMLBody != "" {
+		msg.AddAlternativeString(mail.TypeTextHTML, message.HTMLBody)
 	}
 
-	return err
+	return msg, nil
 }
@@ -0,0 +1,122 @@
+package smtp
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/wneessen/go-mail"
+)
+
+const defaultTimeout = 10 * time.Second
+
+// Transport delivers a rendered email.
+type Transport interface {
+	Deliver(from string, msg *Message) error
+}
+
+type SMTPTransport struct {
+	client *mail.Client
+}
+
+func NewSMTPTransport(host string, port int, username, password string) (*SMTPTransport, error) {
+	client, err := mail.NewClient(host, mail.WithTimeout(defaultTimeout), mail.WithSMTPAuth(mail.SMTPAuthLogin), mail.WithPort(port), mail.WithUsername(username), mail.WithPassword(password))
+	if err != nil {
+		return nil, err
+	}
+
+	return &SMTPTransport{client: client}, nil
+}
+
+func (t *SMTPTransport) Deliver(from string, message *Message) error {
+	msg, err := newMsg(from, message)
+	if err != nil {
+		return err
+	}
+
+	return t.client.DialAndSend(msg)
+}
+
+// FileTransport writes each email to a .eml file in a directory, which can be
+// opened with most mail clients. It is intended for local development.
+type FileTransport struct {
+	dir   string
+	count atomic.Int64
+}
+
+func NewFileTransport(dir string) (*FileTransport, error) {
+	err := os.MkdirAll(dir, 0o755)
+	if err != nil {
+		return nil, err
+	}
+
+	return &FileTransport{dir: dir}, nil
+}
+
+func (t *FileTransport) Deliver(from string, message *Message) error {
+	msg, err := newMsg(from, message)
+	if err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("%s-%d.eml", time.Now().UTC().Format("20060102T150405.000000000"), t.count.Add(1))
+
+	return msg.WriteToFile(filepath.Join(t.dir, name))
+}
+
+// MemoryTransport records delivered emails instead of sending them, so that
+// tests can check what would have been sent.
+type MemoryTransport struct {
+	mu       sync.Mutex
+	messages []Message
+}
+
+func NewMemoryTransport() *MemoryTransport {
+	return &MemoryTransport{}
+}
+
+func (t *MemoryTransport) Deliver(from string, message *Message) error {
+	_, err := newMsg(from, message)
+	if err != nil {
+		return err
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.messages = append(t.messages, *message)
+	return nil
+}
+
+// Messages returns a copy of the emails delivered so far, oldest first.
+func (t *MemoryTransport) Messages() []Message {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	messages := make([]Message, len(t.messages))
+	copy(messages, t.messages)
+
+	return messages
+}
+
+// Last returns the most recently delivered email.
+func (t *MemoryTransport) Last() (Message, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if len(t.messages) == 0 {
+		return Message{}, false
+	}
+
+	return t.messages[len(t.messages)-1], true
+}
+
+func (t *MemoryTransport) Reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.messages = nil
+}
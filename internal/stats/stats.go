@@ -0,0 +1,159 @@
+package stats
+
+import (
+	"sort"
+	"time"
+
+	"github.com/afoejoe/football-predict/internal/market"
+)
+
+// Record is a single settled bet. A zero Stake is treated as one unit, which is
+// how the public track record is worked out.
+type Record struct {
+	Competition string
+	Market      market.Market
+	Odds        float64
+	Stake       float64
+	Outcome     market.Outcome
+	ScheduledAt time.Time
+}
+
+// Summary aggregates a set of settled bets. Voids and pushes return the stake,
+// so they are counted but are excluded from the hit rate, staked units and
+// average odds.
+type Summary struct {
+	Predictions          int
+	Won                  int
+	HalfWon              int
+	Push                 int
+	Void                 int
+	HalfLost             int
+	Lost                 int
+	HitRate              float64 // percentage of graded predictions that won or half won
+	Staked               float64
+	Profit               float64
+	Yield                float64 // profit as a percentage of units staked
+	AverageOdds          float64
+	LongestWinningStreak int
+	LongestLosingStreak  int
+}
+
+type Breakdown struct {
+	Key string
+	Summary
+}
+
+type Report struct {
+	Overall       Summary
+	ByCompetition []Breakdown
+	ByMarket      []Breakdown
+	ByMonth       []Breakdown
+}
+
+// Compute builds a report from settled predictions. Records that are still
+// pending are ignored.
+func Compute(records []Record) Report {
+	settled := make([]Record, 0, len(records))
+	for _, record := range records {
+		if record.Outcome.Settled() {
+			settled = append(settled, record)
+		}
+	}
+
+	sort.SliceStable(settled, func(i, j int) bool {
+		return settled[i].ScheduledAt.Before(settled[j].ScheduledAt)
+	})
+
+	return Report{
+		Overall:       Summarize(settled),
+		ByCompetition: breakdown(settled, func(r Record) string { return r.Competition }),
+		ByMarket:      breakdown(settled, func(r Record) string { return r.Market.Name() }),
+		ByMonth:       breakdown(settled, func(r Record) string { return r.ScheduledAt.Format("2006-01") }),
+	}
+}
+
+// Summarize aggregates records which must already be in chronological order
+// for the streaks to be meaningful.
+func Summarize(records []Record) Summary {
+	var (
+		summary               Summary
+		totalOdds             float64
+		graded                int
+		winStreak, loseStreak int
+	)
+
+	for _, record := range records {
+		summary.Predictions++
+
+		switch record.Outcome {
+		case market.Won:
+			summary.Won++
+		case market.HalfWon:
+			summary.HalfWon++
+		case market.Push:
+			summary.Push++
+		case market.Void:
+			summary.Void++
+		case market.HalfLost:
+			summary.HalfLost++
+		case market.Lost:
+			summary.Lost++
+		}
+
+		switch record.Outcome {
+		case market.Won, market.HalfWon:
+			winStreak++
+			loseStreak = 0
+		case market.Lost, market.HalfLost:
+			loseStreak++
+			winStreak = 0
+		default:
+			continue
+		}
+
+		summary.LongestWinningStreak = max(summary.LongestWinningStreak, winStreak)
+		summary.LongestLosingStreak = max(summary.LongestLosingStreak, loseStreak)
+
+		stake := record.Stake
+		if stake == 0 {
+			stake = 1
+		}
+
+		graded++
+		summary.Staked += stake
+		summary.Profit += stake * record.Outcome.Profit(record.Odds)
+		totalOdds += record.Odds
+	}
+
+	if graded > 0 {
+		summary.HitRate = float64(summary.Won+summary.HalfWon) / float64(graded) * 100
+		summary.Yield = summary.Profit / summary.Staked * 100
+		summary.AverageOdds = totalOdds / float64(graded)
+	}
+
+	return summary
+}
+
+func breakdown(records []Record, keyFunc func(Record) string) []Breakdown {
+	groups := map[string][]Record{}
+	var keys []string
+
+	for _, record := range records {
+		key := keyFunc(record)
+
+		if _, exists := groups[key]; !exists {
+			keys = append(keys, key)
+		}
+
+		groups[key] = append(groups[key], record)
+	}
+
+	sort.Strings(keys)
+
+	breakdowns := make([]Breakdown, 0, len(keys))
+	for _, key := range keys {
+		breakdowns = append(breakdowns, Breakdown{Key: key, Summary: Summarize(groups[key])})
+	}
+
+	return breakdowns
+}
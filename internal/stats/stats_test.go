@@ -0,0 +1,168 @@
+package stats
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/afoejoe/football-predict/internal/market"
+)
+
+func day(month time.Month, d int) time.Time {
+	return time.Date(2024, month, d, 15, 0, 0, 0, time.UTC)
+}
+
+// season is a fixed run of settled predictions in chronological order: three
+// wins either side of a void, four losses either side of a push and a closing
+// half win.
+func season() []Record {
+	return []Record{
+		{Competition: "Premier League", Market: market.MatchResult, Odds: 2.6, Outcome: market.Won, ScheduledAt: day(time.January, 1)},
+		{Competition: "Premier League", Market: market.OverUnder, Odds: 1.5, Outcome: market.Won, ScheduledAt: day(time.January, 8)},
+		{Competition: "La Liga", Market: market.MatchResult, Odds: 3, Outcome: market.Void, ScheduledAt: day(time.January, 15)},
+		{Competition: "La Liga", Market: market.BothTeamsToScore, Odds: 2.5, Outcome: market.Won, ScheduledAt: day(time.January, 22)},
+		{Competition: "Premier League", Market: market.MatchResult, Odds: 2, Outcome: market.Lost, ScheduledAt: day(time.February, 1)},
+		{Competition: "Premier League", Market: market.AsianHandicap, Odds: 1.9, Outcome: market.HalfLost, ScheduledAt: day(time.February, 8)},
+		{Competition: "La Liga", Market: market.MatchResult, Odds: 3, Outcome: market.Lost, ScheduledAt: day(time.February, 15)},
+		{Competition: "La Liga", Market: market.AsianHandicap, Odds: 1.9, Outcome: market.Push, ScheduledAt: day(time.February, 22)},
+		{Competition: "Premier League", Market: market.OverUnder, Odds: 2.2, Outcome: market.Lost, ScheduledAt: day(time.March, 1)},
+		{Competition: "Premier League", Market: market.AsianHandicap, Odds: 2, Outcome: market.HalfWon, ScheduledAt: day(time.March, 8)},
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	tests := []struct {
+		name    string
+		records []Record
+		want    Summary
+	}{
+		{
+			name:    "no records",
+			records: nil,
+			want:    Summary{},
+		},
+		{
+			name:    "only voids and pushes",
+			records: []Record{{Odds: 2, Outcome: market.Void}, {Odds: 1.9, Outcome: market.Push}},
+			want:    Summary{Predictions: 2, Void: 1, Push: 1},
+		},
+		{
+			name:    "season",
+			records: season(),
+			want: Summary{
+				Predictions:          10,
+				Won:                  3,
+				HalfWon:              1,
+				Push:                 1,
+				Void:                 1,
+				HalfLost:             1,
+				Lost:                 3,
+				HitRate:              50,
+				Staked:               8,
+				Profit:               0.6,
+				Yield:                7.5,
+				AverageOdds:          2.2125,
+				LongestWinningStreak: 3,
+				LongestLosingStreak:  4,
+			},
+		},
+		{
+			name: "stakes",
+			records: []Record{
+				{Odds: 2, Stake: 2, Outcome: market.Won},
+				{Odds: 3, Outcome: market.Lost},
+				{Odds: 1.8, Stake: 4, Outcome: market.HalfLost},
+			},
+			want: Summary{
+				Predictions:          3,
+				Won:                  1,
+				HalfLost:             1,
+				Lost:                 1,
+				HitRate:              100.0 / 3,
+				Staked:               7,
+				Profit:               -1,
+				Yield:                -100.0 / 7,
+				AverageOdds:          6.8 / 3,
+				LongestWinningStreak: 1,
+				LongestLosingStreak:  2,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Summarize(tt.records)
+
+			if !equalSummaries(got, tt.want) {
+				t.Errorf("got %+v; want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompute(t *testing.T) {
+	records := season()
+
+	shuffled := []Record{{Competition: "Serie A", Market: market.MatchResult, Odds: 2, Outcome: market.Pending, ScheduledAt: day(time.March, 15)}}
+	for _, i := range []int{9, 4, 0, 7, 2, 5, 8, 1, 6, 3} {
+		shuffled = append(shuffled, records[i])
+	}
+
+	report := Compute(shuffled)
+
+	if want := Summarize(records); !equalSummaries(report.Overall, want) {
+		t.Errorf("got overall %+v; want %+v", report.Overall, want)
+	}
+
+	tests := []struct {
+		name       string
+		breakdowns []Breakdown
+		want       []string
+	}{
+		{"competition", report.ByCompetition, []string{"La Liga", "Premier League"}},
+		{"month", report.ByMonth, []string{"2024-01", "2024-02", "2024-03"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var keys []string
+			predictions := 0
+			for _, breakdown := range tt.breakdowns {
+				keys = append(keys, breakdown.Key)
+				predictions += breakdown.Predictions
+			}
+
+			if len(keys) != len(tt.want) {
+				t.Fatalf("got keys %q; want %q", keys, tt.want)
+			}
+			for i := range keys {
+				if keys[i] != tt.want[i] {
+					t.Errorf("got keys %q; want %q", keys, tt.want)
+				}
+			}
+
+			if predictions != 10 {
+				t.Errorf("got %d predictions across breakdowns; want 10", predictions)
+			}
+		})
+	}
+
+	february := report.ByMonth[1].Summary
+	if february.LongestLosingStreak != 3 || february.Profit != -2.5 || february.Push != 1 {
+		t.Errorf("got February %+v; want a losing streak of 3, a profit of -2.5 and one push", february)
+	}
+}
+
+func equalSummaries(a, b Summary) bool {
+	near := func(x, y float64) bool { return math.Abs(x-y) < 1e-9 }
+
+	if !near(a.HitRate, b.HitRate) || !near(a.Staked, b.Staked) || !near(a.Profit, b.Profit) ||
+		!near(a.Yield, b.Yield) || !near(a.AverageOdds, b.AverageOdds) {
+		return false
+	}
+
+	a.HitRate, a.Staked, a.Profit, a.Yield, a.AverageOdds = 0, 0, 0, 0, 0
+	b.HitRate, b.Staked, b.Profit, b.Yield, b.AverageOdds = 0, 0, 0, 0, 0
+
+	return a == b
+}
@@ -0,0 +1,28 @@
+package token
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"strings"
+)
+
+// New returns a random token with 160 bits of entropy, encoded as lowercase
+// base32 so that it is safe to use in URLs.
+func New() (string, error) {
+	randomBytes := make([]byte, 20)
+
+	_, err := rand.Read(randomBytes)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.ToLower(base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(randomBytes)), nil
+}
+
+// Hash returns the SHA-256 hash of a plaintext token. Only hashes are stored,
+// so a leaked database cannot be used to authenticate.
+func Hash(plaintext string) []byte {
+	hash := sha256.Sum256([]byte(plaintext))
+	return hash[:]
+}
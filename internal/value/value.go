@@ -0,0 +1,91 @@
+// Package value works out whether odds are worth betting at, given how likely
+// the selection is to win, lose or have the stake returned.
+package value
+
+import "github.com/afoejoe/football-predict/internal/market"
+
+// Probabilities splits the stake on a bet by how it is expected to be
+// settled: Win is paid at the odds, Loss is lost and Push is returned.
+type Probabilities struct {
+	Win  float64
+	Push float64
+	Loss float64
+}
+
+// NewProbabilities combines the probability of each settlement outcome. A
+// half win pays half the stake at the odds and returns the other half, and a
+// half loss loses half and returns the other half, so both count half towards
+// a push.
+func NewProbabilities(outcomes map[market.Outcome]float64) Probabilities {
+	return Probabilities{
+		Win:  outcomes[market.Won] + outcomes[market.HalfWon]/2,
+		Push: outcomes[market.Push] + outcomes[market.Void] + outcomes[market.HalfWon]/2 + outcomes[market.HalfLost]/2,
+		Loss: outcomes[market.Lost] + outcomes[market.HalfLost]/2,
+	}
+}
+
+// FairOdds returns the decimal odds at which the expected profit is zero. It
+// returns false if the bet can never win.
+func (p Probabilities) FairOdds() (float64, bool) {
+	if p.Win == 0 {
+		return 0, false
+	}
+
+	return (p.Win + p.Loss) / p.Win, true
+}
+
+// ExpectedValue returns the expected profit, as a percentage of the stake, of
+// a bet at decimal odds. Pushes neither win nor lose anything.
+func ExpectedValue(p Probabilities, odds float64) float64 {
+	return (p.Win*(odds-1) - p.Loss) * 100
+}
+
+// KellyStake returns the stake, as a percentage of the bankroll, recommended
+// by the Kelly criterion scaled by fraction. With net odds b, the full Kelly
+// stake is (win*b - loss) / (b * (win + loss)), which reduces to the usual
+// formula when nothing can push. Full Kelly (a fraction of 1) maximises long
+// run growth but swings wildly when the probabilities are a little
+// optimistic, so a quarter or half is usual. Bets without positive expected
+// value get a stake of zero.
+func KellyStake(p Probabilities, odds, fraction float64) float64 {
+	b := odds - 1
+	if b <= 0 || p.Win+p.Loss == 0 {
+		return 0
+	}
+
+	kelly := (p.Win*b - p.Loss) / (b * (p.Win + p.Loss))
+	if kelly <= 0 {
+		return 0
+	}
+
+	return kelly * fraction * 100
+}
+
+type Options struct {
+	// Threshold is the expected value, as a percentage, above which a bet is
+	// flagged as a value bet.
+	Threshold     float64
+	KellyFraction float64
+}
+
+type Assessment struct {
+	Probabilities Probabilities
+	FairOdds      float64
+	ExpectedValue float64
+	KellyStake    float64
+	IsValue       bool
+}
+
+// Assess evaluates a bet at odds against a model's probabilities for it.
+func Assess(p Probabilities, odds float64, opts Options) Assessment {
+	fairOdds, _ := p.FairOdds()
+	expectedValue := ExpectedValue(p, odds)
+
+	return Assessment{
+		Probabilities: p,
+		FairOdds:      fairOdds,
+		ExpectedValue: expectedValue,
+		KellyStake:    KellyStake(p, odds, opts.KellyFraction),
+		IsValue:       expectedValue > opts.Threshold,
+	}
+}
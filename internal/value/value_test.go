@@ -0,0 +1,96 @@
+package value
+
+import (
+	"math"
+	"testing"
+
+	"github.com/afoejoe/football-predict/internal/market"
+)
+
+func TestNewProbabilities(t *testing.T) {
+	got := NewProbabilities(map[market.Outcome]float64{
+		market.Won:      0.3,
+		market.HalfWon:  0.2,
+		market.Push:     0.1,
+		market.HalfLost: 0.2,
+		market.Lost:     0.2,
+	})
+
+	want := Probabilities{Win: 0.4, Push: 0.3, Loss: 0.3}
+
+	if !approxEqual(got.Win, want.Win) || !approxEqual(got.Push, want.Push) || !approxEqual(got.Loss, want.Loss) {
+		t.Errorf("got %+v; want %+v", got, want)
+	}
+}
+
+func TestAssess(t *testing.T) {
+	tests := []struct {
+		name          string
+		probabilities Probabilities
+		odds          float64
+		fairOdds      float64
+		expectedValue float64
+		kellyStake    float64
+		isValue       bool
+	}{
+		{
+			name:          "no pushes",
+			probabilities: Probabilities{Win: 0.5, Loss: 0.5},
+			odds:          2.2,
+			fairOdds:      2,
+			expectedValue: 10,
+			kellyStake:    8.333333,
+			isValue:       true,
+		},
+		{
+			name:          "pushes return the stake",
+			probabilities: Probabilities{Win: 0.4, Push: 0.25, Loss: 0.35},
+			odds:          2.1,
+			fairOdds:      1.875,
+			expectedValue: 9,
+			kellyStake:    10.909091,
+			isValue:       true,
+		},
+		{
+			name:          "fair odds have no value",
+			probabilities: Probabilities{Win: 0.3, Push: 0.4, Loss: 0.3},
+			odds:          2,
+			fairOdds:      2,
+			expectedValue: 0,
+			kellyStake:    0,
+			isValue:       false,
+		},
+		{
+			name:          "short odds",
+			probabilities: Probabilities{Win: 0.3, Push: 0.2, Loss: 0.5},
+			odds:          2,
+			fairOdds:      2.666667,
+			expectedValue: -20,
+			kellyStake:    0,
+			isValue:       false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Assess(tt.probabilities, tt.odds, Options{Threshold: 5, KellyFraction: 1})
+
+			if !approxEqual(got.FairOdds, tt.fairOdds) {
+				t.Errorf("got fair odds %v; want %v", got.FairOdds, tt.fairOdds)
+			}
+			if !approxEqual(got.ExpectedValue, tt.expectedValue) {
+				t.Errorf("got expected value %v; want %v", got.ExpectedValue, tt.expectedValue)
+			}
+			if !approxEqual(got.KellyStake, tt.kellyStake) {
+				t.Errorf("got Kelly stake %v; want %v", got.KellyStake, tt.kellyStake)
+			}
+			if got.IsValue != tt.isValue {
+				t.Errorf("got IsValue %t; want %t", got.IsValue, tt.isValue)
+			}
+		})
+	}
+}
+
+func approxEqual(a, b float64) bool {
+	return math.Abs(a-b) < 1e-6
+}